@@ -1,8 +1,90 @@
 package cmd
 
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+const (
+	FlagChainBroadcastMode      = "chain.broadcast-mode"
+	FlagChainGasAdjustment      = "chain.gas-adjustment"
+	FlagChainGas                = "chain.gas"
+	FlagChainGasPrices          = "chain.gas-prices"
+	FlagChainID                 = "chain.id"
+	FlagChainRPCAddress         = "chain.rpc-address"
+	FlagChainRPCFallbacks       = "chain.rpc-fallback-addresses"
+	FlagChainSimulateAndExecute = "chain.simulate-and-execute"
+	FlagNodeTimeout             = "node.timeout"
+)
+
 const (
 	flagCORSAllowedOrigins = "cors.allowed-origins"
+	flagKeyringBackend     = "keyring.backend"
 	flagListenURL          = "listen-url"
+	flagLogLevel           = "log.level"
+	flagShutdownTimeout    = "shutdown-timeout"
 	flagTLSCrt             = "tls-crt"
+	flagTLSEnabled         = "tls-enabled"
 	flagTLSKey             = "tls-key"
 )
+
+// ApplyRootFlags overrides the config with the root command flags that were
+// given a value other than their default.
+func ApplyRootFlags(cfg, defCfg *types.Config) {
+	if viper.GetString(FlagChainBroadcastMode) != defCfg.Chain.BroadcastMode {
+		cfg.Chain.BroadcastMode = viper.GetString(FlagChainBroadcastMode)
+	}
+	if viper.GetFloat64(FlagChainGasAdjustment) != defCfg.Chain.GasAdjustment {
+		cfg.Chain.GasAdjustment = viper.GetFloat64(FlagChainGasAdjustment)
+	}
+	if viper.GetString(FlagChainGasPrices) != defCfg.Chain.GasPrices {
+		cfg.Chain.GasPrices = viper.GetString(FlagChainGasPrices)
+	}
+	if viper.GetUint64(FlagChainGas) != defCfg.Chain.Gas {
+		cfg.Chain.Gas = viper.GetUint64(FlagChainGas)
+	}
+	if viper.GetString(FlagChainID) != defCfg.Chain.ID {
+		cfg.Chain.ID = viper.GetString(FlagChainID)
+	}
+	if viper.GetString(FlagChainRPCAddress) != defCfg.Chain.RPCAddress {
+		cfg.Chain.RPCAddress = viper.GetString(FlagChainRPCAddress)
+	}
+	if fallbacks := viper.GetStringSlice(FlagChainRPCFallbacks); strings.Join(fallbacks, ",") != strings.Join(defCfg.Chain.RPCFallbackAddresses, ",") {
+		cfg.Chain.RPCFallbackAddresses = fallbacks
+	}
+	if viper.GetBool(FlagChainSimulateAndExecute) != defCfg.Chain.SimulateAndExecute {
+		cfg.Chain.SimulateAndExecute = viper.GetBool(FlagChainSimulateAndExecute)
+	}
+	if viper.GetUint64(FlagNodeTimeout) != defCfg.Node.Timeout {
+		cfg.Node.Timeout = viper.GetUint64(FlagNodeTimeout)
+	}
+}
+
+// applyServerFlags overrides the config with the server command flags that
+// were given a value other than their default.
+func applyServerFlags(cfg, defCfg *types.Config) {
+	if origins := viper.GetStringSlice(flagCORSAllowedOrigins); strings.Join(origins, ",") != strings.Join(defCfg.CORS.AllowedOrigins, ",") {
+		cfg.CORS.AllowedOrigins = origins
+	}
+	if viper.GetString(flagKeyringBackend) != defCfg.Keyring.Backend {
+		cfg.Keyring.Backend = viper.GetString(flagKeyringBackend)
+	}
+	if viper.GetString(flagListenURL) != defCfg.Server.ListenURL {
+		cfg.Server.ListenURL = viper.GetString(flagListenURL)
+	}
+	if viper.GetString(flagLogLevel) != defCfg.Log.Level {
+		cfg.Log.Level = viper.GetString(flagLogLevel)
+	}
+	if viper.GetString(flagTLSCrt) != defCfg.Server.TLSCrt {
+		cfg.Server.TLSCrt = viper.GetString(flagTLSCrt)
+	}
+	if viper.GetBool(flagTLSEnabled) != defCfg.Server.TLSEnabled {
+		cfg.Server.TLSEnabled = viper.GetBool(flagTLSEnabled)
+	}
+	if viper.GetString(flagTLSKey) != defCfg.Server.TLSKey {
+		cfg.Server.TLSKey = viper.GetString(flagTLSKey)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/lite"
+	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+// reloadConfig reads the config file again, with the flags applied on top as
+// on startup, and applies it to the running server without touching active
+// sessions.
+//
+// The chain section is applied to the client, with a new RPC client when the
+// addresses change, and the rate limit to a new session limiter. Everything
+// else but the settings below is read from the config on each use, so it
+// takes effect right away, or with the next session for the bandwidth,
+// reconnect, session and wireguard sections.
+//
+// The CORS origins, the keyring backend and the server listen URL and TLS
+// settings are only read on startup. They keep their current values, with a
+// warning when the file changes them.
+func reloadConfig(ctx *context.Context, path string) error {
+	var (
+		cfg     = types.NewConfig()
+		defCfg  = types.NewConfig().WithDefaultValues()
+		current = ctx.Config()
+	)
+
+	if err := cfg.LoadFromPath(path); err != nil {
+		return err
+	}
+
+	// Startup rewrites a file of another version with the defaults, which a
+	// reload must not do to a file the user is editing.
+	if cfg.Version != defCfg.Version {
+		return fmt.Errorf("invalid version %d; expected %d, restart to migrate the config", cfg.Version, defCfg.Version)
+	}
+
+	ApplyRootFlags(cfg, defCfg)
+	applyServerFlags(cfg, defCfg)
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	restart := []struct {
+		name    string
+		changed bool
+	}{
+		{"cors->allowed_origins", strings.Join(cfg.CORS.AllowedOrigins, ",") != strings.Join(current.CORS.AllowedOrigins, ",")},
+		{"keyring->backend", cfg.Keyring.Backend != current.Keyring.Backend},
+		{"server->listen_url", cfg.Server.ListenURL != current.Server.ListenURL},
+		{"server->tls_crt", cfg.Server.TLSCrt != current.Server.TLSCrt},
+		{"server->tls_enabled", cfg.Server.TLSEnabled != current.Server.TLSEnabled},
+		{"server->tls_key", cfg.Server.TLSKey != current.Server.TLSKey},
+	}
+	for _, item := range restart {
+		if item.changed {
+			log.Printf("Config setting %s takes effect only after a restart", item.name)
+		}
+	}
+
+	cfg.CORS.AllowedOrigins = append([]string(nil), current.CORS.AllowedOrigins...)
+	cfg.Keyring.Backend = current.Keyring.Backend
+	cfg.Server.ListenURL = current.Server.ListenURL
+	cfg.Server.TLSCrt = current.Server.TLSCrt
+	cfg.Server.TLSEnabled = current.Server.TLSEnabled
+	cfg.Server.TLSKey = current.Server.TLSKey
+
+	var rpc rpcclient.Client
+	if strings.Join(cfg.RPCAddresses(), ",") != strings.Join(current.RPCAddresses(), ",") {
+		v, err := lite.NewRPCClient(cfg.RPCAddresses()...)
+		if err != nil {
+			return fmt.Errorf("invalid chain->rpc_address; %s", err)
+		}
+
+		rpc = v
+	}
+
+	if cfg.RateLimit != current.RateLimit {
+		ctx.WithSessionLimiter(utils.NewRateLimiter(cfg.RateLimit.SessionsPerMinute/60, cfg.RateLimit.SessionBurst))
+	}
+
+	// The client is rebuilt from the current one under the context lock, so
+	// that a keyring Lock or Unlock meanwhile is kept.
+	ctx.WithConfigAndClient(cfg, func(v *lite.Client) *lite.Client {
+		client := v.Copy().
+			WithBroadcastMode(cfg.Chain.BroadcastMode).
+			WithChainID(cfg.Chain.ID).
+			WithGas(cfg.Chain.Gas).
+			WithGasAdjustment(cfg.Chain.GasAdjustment).
+			WithGasPrices(cfg.Chain.GasPrices).
+			WithQueryAttempts(cfg.Chain.QueryAttempts).
+			WithQueryBackoff(time.Duration(cfg.Chain.QueryBackoff) * time.Second).
+			WithSimulateAndExecute(cfg.Chain.SimulateAndExecute)
+		if rpc != nil {
+			client.WithNodeURI(cfg.Chain.RPCAddress).
+				WithClient(rpc)
+		}
+
+		return client
+	})
+
+	log.Printf("Reloaded the config from %s", path)
+	return nil
+}
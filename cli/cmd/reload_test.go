@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/spf13/viper"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/lite"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func newReloadContext(t *testing.T) (*context.Context, string) {
+	t.Helper()
+
+	// The flags are unset, so they read as their defaults like on a start
+	// without any.
+	defCfg := types.NewConfig().WithDefaultValues()
+	for key, value := range map[string]interface{}{
+		FlagChainBroadcastMode:      defCfg.Chain.BroadcastMode,
+		FlagChainGasAdjustment:      defCfg.Chain.GasAdjustment,
+		FlagChainGasPrices:          defCfg.Chain.GasPrices,
+		FlagChainGas:                defCfg.Chain.Gas,
+		FlagChainID:                 defCfg.Chain.ID,
+		FlagChainRPCAddress:         defCfg.Chain.RPCAddress,
+		FlagChainRPCFallbacks:       defCfg.Chain.RPCFallbackAddresses,
+		FlagChainSimulateAndExecute: defCfg.Chain.SimulateAndExecute,
+		FlagNodeTimeout:             defCfg.Node.Timeout,
+		flagCORSAllowedOrigins:      defCfg.CORS.AllowedOrigins,
+		flagKeyringBackend:          defCfg.Keyring.Backend,
+		flagListenURL:               defCfg.Server.ListenURL,
+		flagLogLevel:                defCfg.Log.Level,
+		flagTLSCrt:                  defCfg.Server.TLSCrt,
+		flagTLSEnabled:              defCfg.Server.TLSEnabled,
+		flagTLSKey:                  defCfg.Server.TLSKey,
+	} {
+		viper.SetDefault(key, value)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := types.NewConfig().WithDefaultValues().SaveToPath(path); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.NewContext().
+		WithConfig(types.NewConfig().WithDefaultValues()).
+		WithClient(lite.NewDefaultClient())
+
+	return ctx, path
+}
+
+func TestReloadConfigConcurrentReads(t *testing.T) {
+	ctx, path := newReloadContext(t)
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				_ = ctx.Config().Session.ExpiryInterval
+				_ = ctx.SessionLimiter()
+				_ = ctx.Client()
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := reloadConfig(ctx, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestReloadConfigVersionMismatch(t *testing.T) {
+	ctx, path := newReloadContext(t)
+
+	cfg := types.NewConfig().WithDefaultValues()
+	cfg.Version--
+	if err := cfg.SaveToPath(path); err != nil {
+		t.Fatal(err)
+	}
+
+	current := ctx.Config()
+	err := reloadConfig(ctx, path)
+	if err == nil || !strings.Contains(err.Error(), "invalid version") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+	if ctx.Config() != current {
+		t.Fatal("config replaced despite version mismatch")
+	}
+}
+
+// testKeyring wraps a keyring behind a pointer, so that tests can tell two of
+// them apart by comparison.
+type testKeyring struct {
+	keyring.Keyring
+}
+
+func TestReloadConfigKeepsKeyring(t *testing.T) {
+	ctx, path := newReloadContext(t)
+
+	var (
+		locked   = &testKeyring{keyring.NewInMemory()}
+		unlocked = &testKeyring{keyring.NewInMemory()}
+	)
+
+	ctx.WithClient(ctx.Client().WithKeyring(locked))
+	ctx.Unlock(unlocked, "passphrase", time.Hour)
+
+	if err := reloadConfig(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Client().Keyring() != unlocked {
+		t.Fatal("expected the reload to keep the unlocked keyring")
+	}
+
+	ctx.Lock()
+	if err := reloadConfig(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Client().Keyring() != locked {
+		t.Fatal("expected the reload to keep the locked keyring")
+	}
+}
@@ -1,62 +1,74 @@
 package cmd
 
 import (
-	"fmt"
+	gocontext "context"
+	"encoding/json"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
-	neturl "net/url"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/std"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	"github.com/sentinel-official/hub"
 	"github.com/sentinel-official/hub/params"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
 
 	"github.com/sentinel-official/desktop-client/cli/context"
 	"github.com/sentinel-official/desktop-client/cli/lite"
+	"github.com/sentinel-official/desktop-client/cli/metrics"
 	"github.com/sentinel-official/desktop-client/cli/middlewares"
 	"github.com/sentinel-official/desktop-client/cli/rest/account"
 	"github.com/sentinel-official/desktop-client/cli/rest/bank"
 	"github.com/sentinel-official/desktop-client/cli/rest/config"
 	"github.com/sentinel-official/desktop-client/cli/rest/deposit"
 	"github.com/sentinel-official/desktop-client/cli/rest/distribution"
+	"github.com/sentinel-official/desktop-client/cli/rest/favorite"
 	"github.com/sentinel-official/desktop-client/cli/rest/gov"
+	"github.com/sentinel-official/desktop-client/cli/rest/health"
+	"github.com/sentinel-official/desktop-client/cli/rest/interfaces"
 	"github.com/sentinel-official/desktop-client/cli/rest/keys"
 	"github.com/sentinel-official/desktop-client/cli/rest/node"
+	restparams "github.com/sentinel-official/desktop-client/cli/rest/params"
 	"github.com/sentinel-official/desktop-client/cli/rest/plan"
 	"github.com/sentinel-official/desktop-client/cli/rest/provider"
 	"github.com/sentinel-official/desktop-client/cli/rest/service"
 	"github.com/sentinel-official/desktop-client/cli/rest/session"
 	"github.com/sentinel-official/desktop-client/cli/rest/staking"
+	"github.com/sentinel-official/desktop-client/cli/rest/status"
 	"github.com/sentinel-official/desktop-client/cli/rest/subscription"
+	"github.com/sentinel-official/desktop-client/cli/rest/version"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
 	"github.com/sentinel-official/desktop-client/cli/types"
 	"github.com/sentinel-official/desktop-client/cli/utils"
 )
 
 func ServerCmd(cfg *types.Config) *cobra.Command {
 	var (
-		listenURL string
-		keyFile   string
-		certFile  string
-		defCfg    = types.NewConfig().WithDefaultValues()
+		timeout time.Duration
+		defCfg  = types.NewConfig().WithDefaultValues()
 	)
 
 	cmd := &cobra.Command{
 		Use:   "server",
 		Short: "Start REST API server",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if viper.GetString(flagCORSAllowedOrigins) != defCfg.CORS.AllowedOrigins {
-				cfg.CORS.AllowedOrigins = viper.GetString(flagCORSAllowedOrigins)
-			}
+			applyServerFlags(cfg, defCfg)
 
-			return nil
+			return cfg.Validate()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			home, err := cmd.Flags().GetString(types.FlagHome)
@@ -68,12 +80,19 @@ func ServerCmd(cfg *types.Config) *cobra.Command {
 			std.RegisterInterfaces(encoding.InterfaceRegistry)
 			hub.ModuleBasics.RegisterInterfaces(encoding.InterfaceRegistry)
 
-			rpcclient, err := rpchttp.New(cfg.Chain.RPCAddress, "/websocket")
+			rpcclient, err := lite.NewRPCClient(cfg.RPCAddresses()...)
 			if err != nil {
 				return err
 			}
 
-			kr, err := keyring.New("sentinel", keyring.BackendOS, home, cmd.InOrStdin())
+			// The file backend stays locked until POST /keys/unlock, rather than
+			// blocking requests on a passphrase prompt.
+			input := cmd.InOrStdin()
+			if cfg.Keyring.Backend == keyring.BackendFile {
+				input = strings.NewReader("")
+			}
+
+			kr, err := keyring.New("sentinel", cfg.Keyring.Backend, home, input)
 			if err != nil {
 				return err
 			}
@@ -89,75 +108,347 @@ func ServerCmd(cfg *types.Config) *cobra.Command {
 				WithKeyring(kr).
 				WithLegacyAmino(encoding.Amino).
 				WithNodeURI(cfg.Chain.RPCAddress).
+				WithQueryAttempts(cfg.Chain.QueryAttempts).
+				WithQueryBackoff(time.Duration(cfg.Chain.QueryBackoff) * time.Second).
 				WithSimulateAndExecute(cfg.Chain.SimulateAndExecute).
 				WithTxConfig(encoding.TxConfig)
 
+			token, err := loadToken(home)
+			if err != nil {
+				return err
+			}
+
 			ctx := context.NewContext().
 				WithHome(home).
 				WithConfig(cfg).
 				WithClient(client).
-				WithToken(utils.RandomStringHex(32))
+				WithToken(token).
+				WithSessionLimiter(utils.NewRateLimiter(cfg.RateLimit.SessionsPerMinute/60, cfg.RateLimit.SessionBurst))
+
+			if err := restoreServices(ctx); err != nil {
+				return err
+			}
+			if len(ctx.ServiceIDs()) == 0 {
+				if err := wireguard.FlushKillSwitch(ctx.Home()); err != nil {
+					return err
+				}
+			}
+
+			if err := metrics.Register(ctx); err != nil {
+				return err
+			}
 
 			var (
 				muxRouter    = mux.NewRouter()
 				prefixRouter = muxRouter.PathPrefix("/api/v1").Subrouter()
 			)
 
-			muxRouter.Use(middlewares.Log)
-			prefixRouter.Use(middlewares.AddHeaders)
+			muxRouter.MethodNotAllowedHandler = middlewares.MethodNotAllowed(muxRouter)
+			muxRouter.Use(middlewares.Log(ctx))
+			muxRouter.Use(middlewares.AddHeaders)
+			muxRouter.Use(middlewares.LimitBody(ctx))
+			health.RegisterRoutes(muxRouter, ctx)
+
 			prefixRouter.Use(middlewares.TokenVerify(ctx))
+			prefixRouter.Name("Metrics").
+				Methods(http.MethodGet).Path("/metrics").
+				Handler(promhttp.Handler())
 			account.RegisterRoutes(prefixRouter, ctx)
 			bank.RegisterRoutes(prefixRouter, ctx)
 			config.RegisterRoutes(prefixRouter, ctx)
 			deposit.RegisterRoutes(prefixRouter, ctx)
 			distribution.RegisterRoutes(prefixRouter, ctx)
+			favorite.RegisterRoutes(prefixRouter, ctx)
 			gov.RegisterRoutes(prefixRouter, ctx)
+			interfaces.RegisterRoutes(prefixRouter, ctx)
 			keys.RegisterRoutes(prefixRouter, ctx)
 			node.RegisterRoutes(prefixRouter, ctx)
+			restparams.RegisterRoutes(prefixRouter, ctx)
 			plan.RegisterRoutes(prefixRouter, ctx)
 			provider.RegisterRoutes(prefixRouter, ctx)
 			service.RegisterRoutes(prefixRouter, ctx)
 			session.RegisterRoutes(prefixRouter, ctx)
 			staking.RegisterRoutes(prefixRouter, ctx)
+			status.RegisterRoutes(prefixRouter, ctx)
 			subscription.RegisterRoutes(prefixRouter, ctx)
+			version.RegisterRoutes(prefixRouter, ctx)
 
-			router := cors.New(
-				cors.Options{
-					AllowedOrigins: strings.Split(cfg.CORS.AllowedOrigins, ","),
-					AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut},
-					AllowedHeaders: []string{"Content-Type", "Authorization"},
-				},
-			).Handler(muxRouter)
+			router := newCORS(cfg.CORS.AllowedOrigins).Handler(muxRouter)
 
-			url, err := neturl.Parse(listenURL)
+			url, err := types.ParseListenURL(cfg.Server.ListenURL)
 			if err != nil {
 				return err
 			}
 
-			switch url.Scheme {
-			case "http", "https":
-			default:
-				return fmt.Errorf("invalid listen URL schema")
+			var (
+				server = &http.Server{
+					Addr:    url.Host,
+					Handler: router,
+				}
+				errs    = make(chan error, 1)
+				signals = make(chan os.Signal, 1)
+				reloads = make(chan os.Signal, 1)
+			)
+
+			// Without a certificate pair configured, a self-signed one is kept
+			// under the home directory for the GUI to pin.
+			certFile, keyFile := cfg.Server.TLSCrt, cfg.Server.TLSKey
+			if cfg.Server.TLSEnabled {
+				if certFile == "" {
+					certFile, keyFile = filepath.Join(home, "tls.crt"), filepath.Join(home, "tls.key")
+					if err := utils.EnsureCert(certFile, keyFile, url.Hostname()); err != nil {
+						return err
+					}
+				}
+
+				fingerprint, err := utils.CertFingerprint(certFile)
+				if err != nil {
+					return err
+				}
+
+				log.Printf("TLS certificate: %s, SHA-256 fingerprint: %s", certFile, fingerprint)
+			}
+
+			var listener net.Listener
+			if url.Scheme == "unix" {
+				listener, err = listenUnix(url.Path)
+				if err != nil {
+					return err
+				}
+
+				defer func() {
+					_ = os.Remove(url.Path)
+				}()
+			}
+
+			log.Printf("URL: %s, TOKEN: %s", cfg.Server.ListenURL, filepath.Join(home, types.TokenFileName))
+			go func() {
+				switch url.Scheme {
+				case "https":
+					errs <- server.ListenAndServeTLS(certFile, keyFile)
+				case "unix":
+					errs <- server.Serve(listener)
+				default:
+					errs <- server.ListenAndServe()
+				}
+			}()
+
+			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+			signal.Notify(reloads, syscall.SIGHUP)
+		wait:
+			for {
+				select {
+				case err := <-errs:
+					return err
+				case <-reloads:
+					if err := reloadConfig(ctx, filepath.Join(home, "config.toml")); err != nil {
+						log.Printf("Failed to reload the config: %s", err)
+					}
+				case sig := <-signals:
+					log.Printf("Received signal %s, shutting down", sig)
+					break wait
+				}
 			}
 
-			log.Printf("URL: %s, TOKEN: %s", listenURL, ctx.Token())
-			switch url.Scheme {
-			case "http":
-				return http.ListenAndServe(url.Host, router)
-			case "https":
-				return http.ListenAndServeTLS(url.Host, certFile, keyFile, router)
-			default:
-				return nil
+			c, cancel := gocontext.WithTimeout(gocontext.Background(), timeout)
+			defer cancel()
+
+			if err := server.Shutdown(c); err != nil {
+				log.Printf("Failed to shut down the server gracefully: %s", err)
 			}
+
+			// The services get a timeout of their own, as the server may have
+			// used all of its.
+			sc, scancel := gocontext.WithTimeout(gocontext.Background(), timeout)
+			defer scancel()
+
+			ctx.Lock()
+			return stopServices(sc, ctx)
 		},
 	}
 
-	cmd.Flags().StringVar(&listenURL, flagListenURL, types.DefaultListenURL, "")
-	cmd.Flags().StringVar(&keyFile, flagTLSKey, filepath.Join(types.DefaultHomeDirectory, "tls.key"), "")
-	cmd.Flags().StringVar(&certFile, flagTLSCrt, filepath.Join(types.DefaultHomeDirectory, "tls.crt"), "")
-	cmd.Flags().String(flagCORSAllowedOrigins, defCfg.CORS.AllowedOrigins, "")
+	cmd.Flags().DurationVar(&timeout, flagShutdownTimeout, 10*time.Second, "")
+	cmd.Flags().StringSlice(flagCORSAllowedOrigins, defCfg.CORS.AllowedOrigins, "")
+	cmd.Flags().String(flagKeyringBackend, defCfg.Keyring.Backend, "")
+	cmd.Flags().String(flagListenURL, defCfg.Server.ListenURL, "")
+	cmd.Flags().String(flagLogLevel, defCfg.Log.Level, "")
+	cmd.Flags().String(flagTLSCrt, defCfg.Server.TLSCrt, "")
+	cmd.Flags().Bool(flagTLSEnabled, defCfg.Server.TLSEnabled, "")
+	cmd.Flags().String(flagTLSKey, defCfg.Server.TLSKey, "")
 
 	_ = viper.BindPFlag(flagCORSAllowedOrigins, cmd.Flags().Lookup(flagCORSAllowedOrigins))
+	_ = viper.BindPFlag(flagKeyringBackend, cmd.Flags().Lookup(flagKeyringBackend))
+	_ = viper.BindPFlag(flagListenURL, cmd.Flags().Lookup(flagListenURL))
+	_ = viper.BindPFlag(flagLogLevel, cmd.Flags().Lookup(flagLogLevel))
+	_ = viper.BindPFlag(flagTLSCrt, cmd.Flags().Lookup(flagTLSCrt))
+	_ = viper.BindPFlag(flagTLSEnabled, cmd.Flags().Lookup(flagTLSEnabled))
+	_ = viper.BindPFlag(flagTLSKey, cmd.Flags().Lookup(flagTLSKey))
 
 	return cmd
 }
+
+// newCORS returns the CORS handler for the origins, which lets the GUI send
+// the headers the API reads and read the ones it sets.
+func newCORS(origins []string) *cors.Cors {
+	return cors.New(
+		cors.Options{
+			AllowedOrigins: origins,
+			AllowedMethods: []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete},
+			AllowedHeaders: []string{"Content-Type", "Authorization", "Idempotency-Key"},
+			ExposedHeaders: []string{"Retry-After"},
+		},
+	)
+}
+
+// listenUnix listens on the socket at path, replacing a stale one left behind
+// by an unclean shutdown, and makes it accessible to the current user only.
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// stopServices stops the services concurrently, waiting for them until the
+// context is done. A service still stopping by then is left to finish on its
+// own, and the kill switch is flushed either way so the network is usable.
+func stopServices(c gocontext.Context, ctx *context.Context) error {
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	for _, id := range ctx.ServiceIDs() {
+		service := ctx.Service(id)
+		if service == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(id uint64, service types.Service) {
+			defer wg.Done()
+
+			if err := utils.StopService(service); err != nil {
+				log.Printf("Failed to stop session %d: %s", id, err)
+			}
+
+			if err := os.Remove(types.StatusFilePath(ctx.Home(), id)); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed to remove status of session %d: %s", id, err)
+			}
+
+			ctx.WithService(id, nil)
+		}(id, service)
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-c.Done():
+		log.Printf("Gave up waiting for sessions %v to stop: %s", ctx.ServiceIDs(), c.Err())
+	}
+
+	return wireguard.FlushKillSwitch(ctx.Home())
+}
+
+func loadToken(home string) (string, error) {
+	path := filepath.Join(home, types.TokenFileName)
+	if data, err := ioutil.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	token := utils.RandomStringHex(32)
+	if err := ioutil.WriteFile(path, []byte(token), 0600); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func restoreServices(ctx *context.Context) error {
+	paths, err := filepath.Glob(filepath.Join(ctx.Home(), "status*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		service, err := restoreService(ctx.Home(), path)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			continue
+		}
+
+		var status types.Status
+		if err := json.Unmarshal(service.Info(), &status); err != nil {
+			return err
+		}
+
+		if newPath := types.StatusFilePath(ctx.Home(), status.ID); newPath != path {
+			if err := status.SaveToPath(newPath); err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+
+		ctx.WithService(status.ID, service)
+		ctx.StartBandwidthSampler(status.ID, service)
+		session.WatchExpiry(ctx, status.ID, service)
+	}
+
+	return nil
+}
+
+func restoreService(home, path string) (types.Service, error) {
+	status := types.NewStatus()
+	if err := status.LoadFromPath(path); err != nil {
+		return nil, err
+	}
+	if status.ID == 0 {
+		return nil, nil
+	}
+
+	if status.Type == "" || status.Type == types.ServiceTypeWireGuard {
+		info, err := json.Marshal(status)
+		if err != nil {
+			return nil, err
+		}
+
+		service := wireguard.NewWireGuard().
+			WithConfig(&wgt.Config{Name: status.Name}).
+			WithConfigDir(home).
+			WithInfo(info).
+			WithDNSGuard(status.DNSGuard).
+			WithKillSwitch(status.KillSwitch)
+		if service.IsUp() {
+			return service, nil
+		}
+
+		if err := service.Cleanup(); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Clearing stale session %d", status.ID)
+	return nil, os.Remove(path)
+}
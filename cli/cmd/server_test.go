@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	gocontext "context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+// stopService is a service whose Stop blocks until release is closed.
+type stopService struct {
+	release chan struct{}
+}
+
+func (s *stopService) Info() []byte                    { return nil }
+func (s *stopService) PreUp() error                    { return nil }
+func (s *stopService) Up() error                       { return nil }
+func (s *stopService) PostUp() error                   { return nil }
+func (s *stopService) PreDown() error                  { return nil }
+func (s *stopService) Down() error                     { return nil }
+func (s *stopService) PostDown() error                 { return nil }
+func (s *stopService) Transfer() (int64, int64, error) { return 0, 0, nil }
+
+func (s *stopService) Stop() error {
+	<-s.release
+	return nil
+}
+
+func TestStopServicesTimeout(t *testing.T) {
+	var (
+		release = make(chan struct{})
+		closed  = make(chan struct{})
+		hung    = &stopService{release: release}
+		ctx     = context.NewContext().
+			WithHome(t.TempDir()).
+			WithService(1, &stopService{release: closed}).
+			WithService(2, hung)
+	)
+
+	close(closed)
+	defer close(release)
+
+	c, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_ = stopServices(c, ctx)
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("expected stopping to give up at the deadline, took %s", d)
+	}
+	if ctx.Service(1) != nil {
+		t.Fatal("expected the stopped session to be forgotten")
+	}
+	if ctx.Service(2) != hung {
+		t.Fatal("expected the hung session to be kept")
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler := newCORS([]string{"http://localhost:3000"}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, header := range []string{"Content-Type", "Authorization", "Idempotency-Key"} {
+		t.Run(header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodOptions, "/api/v1/sessions", nil)
+			r.Header.Set("Origin", "http://localhost:3000")
+			r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			r.Header.Set("Access-Control-Request-Headers", header)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Header().Get("Access-Control-Allow-Origin") == "" {
+				t.Fatalf("expected the preflight with %s to be allowed", header)
+			}
+		})
+	}
+}
+
+func TestCORSExposedHeaders(t *testing.T) {
+	handler := newCORS([]string{"http://localhost:3000"}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/sessions", nil)
+	r.Header.Set("Origin", "http://localhost:3000")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if v := w.Header().Get("Access-Control-Expose-Headers"); v != "Retry-After" {
+		t.Fatalf("expected Retry-After to be exposed, got %q", v)
+	}
+}
@@ -0,0 +1,66 @@
+package context
+
+import (
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func (c *Context) BandwidthSeries(id uint64) *types.BandwidthSeries {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.bandwidth[id]
+}
+
+// StartBandwidthSampler records the transfer counters of the service every
+// bandwidth->interval seconds until it is no longer the active service for
+// the session. The samples are cumulative across counter resets caused by a
+// rekey or reconnect recreating the interface. The series is kept afterwards
+// so that the last one can still be served.
+func (c *Context) StartBandwidthSampler(id uint64, service types.Service) {
+	var (
+		cfg      = c.Config().Bandwidth
+		interval = time.Duration(cfg.Interval) * time.Second
+		series   = types.NewBandwidthSeries(int(cfg.Retention / cfg.Interval))
+	)
+
+	c.mutex.Lock()
+	c.bandwidth[id] = series
+	c.mutex.Unlock()
+
+	var downloaded, uploaded types.BandwidthCounter
+	sample := func() {
+		download, upload, err := service.Transfer()
+		if err != nil {
+			return
+		}
+
+		series.Add(
+			types.BandwidthSample{
+				Time:     time.Now().UTC(),
+				Upload:   uploaded.Update(upload),
+				Download: downloaded.Update(download),
+			},
+		)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		sample()
+		for {
+			select {
+			case <-c.Context().Done():
+				return
+			case <-ticker.C:
+				if c.Service(id) != service {
+					return
+				}
+
+				sample()
+			}
+		}
+	}()
+}
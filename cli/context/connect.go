@@ -0,0 +1,36 @@
+package context
+
+// BeginConnect tracks an in-flight connect for the session id, which cancel
+// aborts. It returns false if one is in flight already.
+func (c *Context) BeginConnect(id uint64, cancel func()) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.connects[id]; ok {
+		return false
+	}
+
+	c.connects[id] = cancel
+	return true
+}
+
+func (c *Context) EndConnect(id uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.connects, id)
+}
+
+// CancelConnect aborts the in-flight connect for the session id, and returns
+// whether there was one.
+func (c *Context) CancelConnect(id uint64) bool {
+	c.mutex.Lock()
+	cancel, ok := c.connects[id]
+	c.mutex.Unlock()
+
+	if ok {
+		cancel()
+	}
+
+	return ok
+}
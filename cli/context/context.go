@@ -2,39 +2,55 @@ package context
 
 import (
 	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 
 	"github.com/sentinel-official/desktop-client/cli/lite"
 	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
 )
 
 type Context struct {
-	home    string
-	token   string
-	ctx     context.Context
-	service types.Service
-	client  *lite.Client
-	config  *types.Config
+	home       string
+	token      string
+	ctx        context.Context
+	mutex      sync.RWMutex
+	services   map[uint64]types.Service
+	bandwidth  map[uint64]*types.BandwidthSeries
+	idempotent map[string]*idempotentEntry
+	connects   map[uint64]func()
+	reachable  map[uint64]*reachableEntry
+	client     *lite.Client
+	config     *types.Config
+	limiter    *utils.RateLimiter
+
+	passphrase string
+	locked     keyring.Keyring
+	lockTimer  *time.Timer
 }
 
 func NewContext() *Context {
 	return &Context{
-		ctx: context.Background(),
+		ctx:        context.Background(),
+		services:   make(map[uint64]types.Service),
+		bandwidth:  make(map[uint64]*types.BandwidthSeries),
+		idempotent: make(map[string]*idempotentEntry),
+		connects:   make(map[uint64]func()),
+		reachable:  make(map[uint64]*reachableEntry),
 	}
 }
 
 func (c *Context) WithHome(v string) *Context             { c.home = v; return c }
 func (c *Context) WithToken(v string) *Context            { c.token = v; return c }
-func (c *Context) WithClient(v *lite.Client) *Context     { c.client = v; return c }
-func (c *Context) WithConfig(v *types.Config) *Context    { c.config = v; return c }
 func (c *Context) WithContext(v context.Context) *Context { c.ctx = v; return c }
-func (c *Context) WithService(v types.Service) *Context   { c.service = v; return c }
 
 func (c *Context) Home() string             { return c.home }
 func (c *Context) Token() string            { return c.token }
-func (c *Context) Client() *lite.Client     { return c.client }
-func (c *Context) Config() *types.Config    { return c.config }
 func (c *Context) Context() context.Context { return c.ctx }
-func (c *Context) Service() types.Service   { return c.service }
 
 func (c *Context) WithValue(key, value interface{}) *Context {
 	c.WithContext(context.WithValue(c.ctx, key, value))
@@ -42,3 +58,156 @@ func (c *Context) WithValue(key, value interface{}) *Context {
 }
 
 func (c *Context) Value(key interface{}) interface{} { return c.ctx.Value(key) }
+
+func (c *Context) WithService(id uint64, v types.Service) *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if v == nil {
+		delete(c.services, id)
+	} else {
+		c.services[id] = v
+	}
+
+	return c
+}
+
+func (c *Context) Service(id uint64) types.Service {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.services[id]
+}
+
+func (c *Context) ServiceIDs() []uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ids := make([]uint64, 0, len(c.services))
+	for id := range c.services {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (c *Context) WithClient(v *lite.Client) *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.client = v
+	return c
+}
+
+func (c *Context) Client() *lite.Client {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.client
+}
+
+func (c *Context) WithConfig(v *types.Config) *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.config = v
+	return c
+}
+
+// WithConfigAndClient swaps in the config and the client fn builds from the
+// current one together, so no caller sees one without the other. The lock is
+// held from reading the current client to the swap, so that a Lock or Unlock
+// in between is not undone; fn must therefore not call into the context.
+func (c *Context) WithConfigAndClient(cfg *types.Config, fn func(*lite.Client) *lite.Client) *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.config = cfg
+	c.client = fn(c.client)
+	return c
+}
+
+func (c *Context) Config() *types.Config {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.config
+}
+
+func (c *Context) WithSessionLimiter(v *utils.RateLimiter) *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.limiter = v
+	return c
+}
+
+func (c *Context) SessionLimiter() *utils.RateLimiter {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.limiter
+}
+
+// Unlock makes the client sign with the unlocked keyring v and keeps its
+// passphrase in memory until Lock is called or ttl elapses.
+func (c *Context) Unlock(v keyring.Keyring, passphrase string, ttl time.Duration) *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.locked == nil {
+		c.locked = c.client.Keyring()
+	}
+	if c.lockTimer != nil {
+		c.lockTimer.Stop()
+	}
+
+	c.passphrase = passphrase
+	c.client = c.client.Copy().WithKeyring(v)
+	c.lockTimer = time.AfterFunc(ttl, func() { c.Lock() })
+
+	return c
+}
+
+func (c *Context) Lock() *Context {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.lockTimer != nil {
+		c.lockTimer.Stop()
+		c.lockTimer = nil
+	}
+	if c.locked != nil {
+		c.client = c.client.Copy().WithKeyring(c.locked)
+		c.locked = nil
+	}
+
+	c.passphrase = ""
+	return c
+}
+
+func (c *Context) Passphrase() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.passphrase
+}
+
+// TunnelDNS returns the DNS servers of the active sessions which have any.
+func (c *Context) TunnelDNS() []net.IP {
+	var items []net.IP
+	for _, id := range c.ServiceIDs() {
+		if v, ok := c.Service(id).(interface{ DNS() []net.IP }); ok {
+			items = append(items, v.DNS()...)
+		}
+	}
+
+	return items
+}
+
+// Resolver resolves names through the tunnel DNS servers while a session has
+// any, so that lookups for tunnel bound features don't go to the ISP resolver.
+func (c *Context) Resolver() *net.Resolver {
+	return utils.NewResolver(c.TunnelDNS())
+}
@@ -0,0 +1,54 @@
+package context
+
+import (
+	"fmt"
+	"time"
+)
+
+type idempotentEntry struct {
+	result    interface{}
+	expiresAt time.Time
+}
+
+// BeginIdempotent returns the result stored for key by an earlier successful
+// request. Otherwise it reserves key until EndIdempotent is called, and fails
+// if another request holds it already.
+func (c *Context) BeginIdempotent(key string) (interface{}, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for k, v := range c.idempotent {
+		if v.result != nil && now.After(v.expiresAt) {
+			delete(c.idempotent, k)
+		}
+	}
+
+	if v, ok := c.idempotent[key]; ok {
+		if v.result == nil {
+			return nil, false, fmt.Errorf("a request with the same idempotency key is in progress")
+		}
+
+		return v.result, true, nil
+	}
+
+	c.idempotent[key] = &idempotentEntry{}
+	return nil, false, nil
+}
+
+// EndIdempotent stores the result for key for ttl, or releases key when the
+// result is nil so that the request can be retried.
+func (c *Context) EndIdempotent(key string, result interface{}, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if result == nil {
+		delete(c.idempotent, key)
+		return
+	}
+
+	c.idempotent[key] = &idempotentEntry{
+		result:    result,
+		expiresAt: time.Now().Add(ttl),
+	}
+}
@@ -0,0 +1,38 @@
+package context
+
+import (
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+type reachableEntry struct {
+	service   types.Service
+	reachable bool
+	expiresAt time.Time
+}
+
+// Reachable returns the connectivity probe result stored for the service of
+// the session, unless it has expired or the service has been replaced since.
+func (c *Context) Reachable(id uint64, service types.Service) (bool, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	v, ok := c.reachable[id]
+	if !ok || v.service != service || time.Now().After(v.expiresAt) {
+		return false, false
+	}
+
+	return v.reachable, true
+}
+
+func (c *Context) WithReachable(id uint64, service types.Service, reachable bool, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.reachable[id] = &reachableEntry{
+		service:   service,
+		reachable: reachable,
+		expiresAt: time.Now().Add(ttl),
+	}
+}
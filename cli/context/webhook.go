@@ -0,0 +1,86 @@
+package context
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+const (
+	WebhookEventSessionStarted = "session_started"
+	WebhookEventSessionStopped = "session_stopped"
+)
+
+type webhookPayload struct {
+	Event       string    `json:"event"`
+	SessionID   uint64    `json:"session_id"`
+	NodeAddress string    `json:"node_address"`
+	Interface   string    `json:"interface"`
+	Time        time.Time `json:"time"`
+}
+
+// NotifyWebhook posts the event for the session to webhook->url, if one is
+// set. It returns right away; failures are only logged, so that the webhook
+// never holds up or fails a session.
+func (c *Context) NotifyWebhook(event string, id uint64, service types.Service) {
+	cfg := c.Config().Webhook
+	if cfg.URL == "" {
+		return
+	}
+
+	payload := webhookPayload{
+		Event:     event,
+		SessionID: id,
+		Time:      time.Now().UTC(),
+	}
+
+	var status types.Status
+	if err := json.Unmarshal(service.Info(), &status); err == nil {
+		payload.NodeAddress = status.To
+		payload.Interface = status.Name
+	}
+
+	go func() {
+		if err := postWebhook(cfg.URL, time.Duration(cfg.Timeout)*time.Second, payload); err != nil {
+			log.Printf("Failed to notify the webhook of %s for session %d: %s", event, id, err)
+		}
+	}()
+}
+
+func postWebhook(url string, timeout time.Duration, payload webhookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	c, cancel := gocontext.WithTimeout(gocontext.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(c, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -6,8 +6,11 @@ require (
 	github.com/cosmos/cosmos-sdk v0.42.5
 	github.com/cosmos/go-bip39 v1.0.0
 	github.com/go-kit/kit v0.10.0
+	github.com/gogo/protobuf v1.3.3
 	github.com/gorilla/mux v1.8.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/pelletier/go-toml v1.8.1
+	github.com/prometheus/client_golang v1.10.0
 	github.com/rs/cors v1.7.0
 	github.com/sentinel-official/hub v0.6.2
 	github.com/spf13/cobra v1.1.3
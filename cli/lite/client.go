@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -20,6 +21,9 @@ type Client struct {
 	ctx   client.Context
 	txf   tx.Factory
 	mutex sync.Mutex
+
+	queryAttempts uint64
+	queryBackoff  time.Duration
 }
 
 func NewClient() *Client {
@@ -36,13 +40,17 @@ func NewDefaultClient() *Client {
 		WithUseLedger(false).
 		WithSimulate(false).
 		WithSkipConfirm(true).
-		WithMemo("")
+		WithMemo("").
+		WithQueryAttempts(1).
+		WithQueryBackoff(0)
 }
 
 func (c *Client) Copy() *Client {
 	return &Client{
-		ctx: c.ctx,
-		txf: c.txf,
+		ctx:           c.ctx,
+		txf:           c.txf,
+		queryAttempts: c.queryAttempts,
+		queryBackoff:  c.queryBackoff,
 	}
 }
 
@@ -66,6 +74,9 @@ func (c *Client) WithSimulate(v bool) *Client                     { c.ctx.Simula
 func (c *Client) WithSkipConfirm(v bool) *Client                  { c.ctx.SkipConfirm = v; return c }
 func (c *Client) WithUseLedger(v bool) *Client                    { c.ctx.UseLedger = v; return c }
 
+func (c *Client) WithQueryAttempts(v uint64) *Client       { c.queryAttempts = v; return c }
+func (c *Client) WithQueryBackoff(v time.Duration) *Client { c.queryBackoff = v; return c }
+
 func (c *Client) WithAccountRetriever(v client.AccountRetriever) *Client {
 	c.ctx.AccountRetriever = v
 	c.txf = c.txf.WithAccountRetriever(v)
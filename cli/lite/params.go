@@ -0,0 +1,44 @@
+package lite
+
+import (
+	"context"
+
+	nodetypes "github.com/sentinel-official/hub/x/node/types"
+	sessiontypes "github.com/sentinel-official/hub/x/session/types"
+	subscriptiontypes "github.com/sentinel-official/hub/x/subscription/types"
+)
+
+type Params struct {
+	Node         nodetypes.Params
+	Subscription subscriptiontypes.Params
+	Session      sessiontypes.Params
+}
+
+// QueryParams returns the parameters of the node, subscription and session
+// modules.
+func (c *Client) QueryParams() (*Params, error) {
+	var (
+		params Params
+		nqc    = nodetypes.NewQueryServiceClient(c.conn())
+		sqc    = subscriptiontypes.NewQueryServiceClient(c.conn())
+		eqc    = sessiontypes.NewQueryServiceClient(c.conn())
+	)
+
+	nres, err := nqc.QueryParams(context.Background(), nodetypes.NewQueryParamsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	sres, err := sqc.QueryParams(context.Background(), subscriptiontypes.NewQueryParamsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	eres, err := eqc.QueryParams(context.Background(), sessiontypes.NewQueryParamsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	params.Node, params.Subscription, params.Session = nres.Params, sres.Params, eres.Params
+	return &params, nil
+}
@@ -23,7 +23,7 @@ import (
 func (c *Client) QueryAccount(address sdk.AccAddress) (authtypes.AccountI, error) {
 	var (
 		account authtypes.AccountI
-		qc      = authtypes.NewQueryClient(c.ctx)
+		qc      = authtypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.Account(context.Background(),
@@ -44,7 +44,7 @@ func (c *Client) QueryAccount(address sdk.AccAddress) (authtypes.AccountI, error
 
 func (c *Client) QueryBalance(address sdk.AccAddress, denom string) (*sdk.Coin, error) {
 	var (
-		qc = banktypes.NewQueryClient(c.ctx)
+		qc = banktypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.Balance(context.Background(),
@@ -60,9 +60,26 @@ func (c *Client) QueryBalance(address sdk.AccAddress, denom string) (*sdk.Coin,
 	return res.Balance, nil
 }
 
+func (c *Client) QueryBalances(address sdk.AccAddress) (sdk.Coins, error) {
+	var (
+		qc = banktypes.NewQueryClient(c.conn())
+	)
+
+	res, err := qc.AllBalances(context.Background(),
+		&banktypes.QueryAllBalancesRequest{
+			Address: address.String(),
+		},
+	)
+	if err != nil {
+		return nil, utils.IsNotFoundError(err)
+	}
+
+	return res.Balances, nil
+}
+
 func (c *Client) QueryValidator(address sdk.ValAddress) (*stakingtypes.Validator, error) {
 	var (
-		qc = stakingtypes.NewQueryClient(c.ctx)
+		qc = stakingtypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.Validator(context.Background(),
@@ -79,7 +96,7 @@ func (c *Client) QueryValidator(address sdk.ValAddress) (*stakingtypes.Validator
 
 func (c *Client) QueryValidators(status string, pagination *query.PageRequest) (stakingtypes.Validators, error) {
 	var (
-		qc = stakingtypes.NewQueryClient(c.ctx)
+		qc = stakingtypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.Validators(context.Background(),
@@ -97,7 +114,7 @@ func (c *Client) QueryValidators(status string, pagination *query.PageRequest) (
 
 func (c *Client) QueryDelegations(address sdk.AccAddress) (stakingtypes.DelegationResponses, error) {
 	var (
-		qc = stakingtypes.NewQueryClient(c.ctx)
+		qc = stakingtypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.DelegatorDelegations(context.Background(),
@@ -114,7 +131,7 @@ func (c *Client) QueryDelegations(address sdk.AccAddress) (stakingtypes.Delegati
 
 func (c *Client) QueryProposals() (govtypes.Proposals, error) {
 	var (
-		qc = govtypes.NewQueryClient(c.ctx)
+		qc = govtypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.Proposals(context.Background(),
@@ -129,7 +146,7 @@ func (c *Client) QueryProposals() (govtypes.Proposals, error) {
 
 func (c *Client) QueryProposalVote(id uint64, address sdk.AccAddress) (*govtypes.Vote, error) {
 	var (
-		qc = govtypes.NewQueryClient(c.ctx)
+		qc = govtypes.NewQueryClient(c.conn())
 	)
 
 	res, err := qc.Vote(context.Background(),
@@ -147,7 +164,7 @@ func (c *Client) QueryProposalVote(id uint64, address sdk.AccAddress) (*govtypes
 
 func (c *Client) QueryDeposit(address sdk.AccAddress) (*deposittypes.Deposit, error) {
 	var (
-		qc = deposittypes.NewQueryServiceClient(c.ctx)
+		qc = deposittypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryDeposit(context.Background(),
@@ -161,7 +178,7 @@ func (c *Client) QueryDeposit(address sdk.AccAddress) (*deposittypes.Deposit, er
 
 func (c *Client) QueryProvider(address hubtypes.ProvAddress) (*providertypes.Provider, error) {
 	var (
-		qc = providertypes.NewQueryServiceClient(c.ctx)
+		qc = providertypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryProvider(context.Background(),
@@ -175,7 +192,7 @@ func (c *Client) QueryProvider(address hubtypes.ProvAddress) (*providertypes.Pro
 
 func (c *Client) QueryProviders(pagination *query.PageRequest) (providertypes.Providers, error) {
 	var (
-		qc = providertypes.NewQueryServiceClient(c.ctx)
+		qc = providertypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryProviders(context.Background(),
@@ -189,7 +206,7 @@ func (c *Client) QueryProviders(pagination *query.PageRequest) (providertypes.Pr
 
 func (c *Client) QueryNode(address hubtypes.NodeAddress) (*nodetypes.Node, error) {
 	var (
-		qc = nodetypes.NewQueryServiceClient(c.ctx)
+		qc = nodetypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryNode(context.Background(),
@@ -203,7 +220,7 @@ func (c *Client) QueryNode(address hubtypes.NodeAddress) (*nodetypes.Node, error
 
 func (c *Client) QueryNodes(status hubtypes.Status, pagination *query.PageRequest) (nodetypes.Nodes, error) {
 	var (
-		qc = nodetypes.NewQueryServiceClient(c.ctx)
+		qc = nodetypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryNodes(context.Background(),
@@ -215,9 +232,40 @@ func (c *Client) QueryNodes(status hubtypes.Status, pagination *query.PageReques
 	return res.Nodes, nil
 }
 
+// QueryAllNodes returns the nodes with the status, paging through all of
+// them.
+func (c *Client) QueryAllNodes(status hubtypes.Status) (nodetypes.Nodes, error) {
+	var (
+		qc         = nodetypes.NewQueryServiceClient(c.conn())
+		items      nodetypes.Nodes
+		pagination = &query.PageRequest{
+			Limit: 100,
+		}
+	)
+
+	for {
+		res, err := qc.QueryNodes(context.Background(),
+			nodetypes.NewQueryNodesRequest(status, pagination))
+		if err != nil {
+			if err = utils.IsNotFoundError(err); err != nil {
+				return nil, err
+			}
+
+			return items, nil
+		}
+
+		items = append(items, res.Nodes...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return items, nil
+		}
+
+		pagination.Key = res.Pagination.NextKey
+	}
+}
+
 func (c *Client) QueryNodesForPlan(id uint64, pagination *query.PageRequest) (nodetypes.Nodes, error) {
 	var (
-		qc = plantypes.NewQueryServiceClient(c.ctx)
+		qc = plantypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryNodesForPlan(context.Background(),
@@ -231,7 +279,7 @@ func (c *Client) QueryNodesForPlan(id uint64, pagination *query.PageRequest) (no
 
 func (c *Client) QueryPlan(id uint64) (*plantypes.Plan, error) {
 	var (
-		qc = plantypes.NewQueryServiceClient(c.ctx)
+		qc = plantypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryPlan(context.Background(),
@@ -245,7 +293,7 @@ func (c *Client) QueryPlan(id uint64) (*plantypes.Plan, error) {
 
 func (c *Client) QueryPlansForProvider(address hubtypes.ProvAddress, status hubtypes.Status, pagination *query.PageRequest) (plantypes.Plans, error) {
 	var (
-		qc = plantypes.NewQueryServiceClient(c.ctx)
+		qc = plantypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryPlansForProvider(context.Background(),
@@ -259,7 +307,7 @@ func (c *Client) QueryPlansForProvider(address hubtypes.ProvAddress, status hubt
 
 func (c *Client) QuerySubscription(id uint64) (*subscriptiontypes.Subscription, error) {
 	var (
-		qc = subscriptiontypes.NewQueryServiceClient(c.ctx)
+		qc = subscriptiontypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QuerySubscription(context.Background(),
@@ -273,7 +321,7 @@ func (c *Client) QuerySubscription(id uint64) (*subscriptiontypes.Subscription,
 
 func (c *Client) QuerySubscriptionsForAddress(address sdk.AccAddress, status hubtypes.Status, pagination *query.PageRequest) (subscriptiontypes.Subscriptions, error) {
 	var (
-		qc = subscriptiontypes.NewQueryServiceClient(c.ctx)
+		qc = subscriptiontypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QuerySubscriptionsForAddress(context.Background(),
@@ -287,7 +335,7 @@ func (c *Client) QuerySubscriptionsForAddress(address sdk.AccAddress, status hub
 
 func (c *Client) QueryQuota(id uint64, address sdk.AccAddress) (*subscriptiontypes.Quota, error) {
 	var (
-		qc = subscriptiontypes.NewQueryServiceClient(c.ctx)
+		qc = subscriptiontypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryQuota(context.Background(),
@@ -301,7 +349,7 @@ func (c *Client) QueryQuota(id uint64, address sdk.AccAddress) (*subscriptiontyp
 
 func (c *Client) QueryQuotas(id uint64, pagination *query.PageRequest) (subscriptiontypes.Quotas, error) {
 	var (
-		qc = subscriptiontypes.NewQueryServiceClient(c.ctx)
+		qc = subscriptiontypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QueryQuotas(context.Background(),
@@ -315,7 +363,7 @@ func (c *Client) QueryQuotas(id uint64, pagination *query.PageRequest) (subscrip
 
 func (c *Client) QuerySession(id uint64) (*sessiontypes.Session, error) {
 	var (
-		qc = sessiontypes.NewQueryServiceClient(c.ctx)
+		qc = sessiontypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QuerySession(context.Background(),
@@ -327,16 +375,108 @@ func (c *Client) QuerySession(id uint64) (*sessiontypes.Session, error) {
 	return &res.Session, nil
 }
 
-func (c *Client) QuerySessionsForAddress(address sdk.AccAddress, status hubtypes.Status, pagination *query.PageRequest) (sessiontypes.Sessions, error) {
+func (c *Client) QuerySessionsForNode(address hubtypes.NodeAddress, pagination *query.PageRequest) (sessiontypes.Sessions, *query.PageResponse, error) {
+	var (
+		qc = sessiontypes.NewQueryServiceClient(c.conn())
+	)
+
+	res, err := qc.QuerySessionsForNode(context.Background(),
+		sessiontypes.NewQuerySessionsForNodeRequest(address, pagination))
+	if err != nil {
+		return nil, nil, utils.IsNotFoundError(err)
+	}
+
+	return res.Sessions, res.Pagination, nil
+}
+
+func (c *Client) QuerySessionsForAddress(address sdk.AccAddress, status hubtypes.Status, pagination *query.PageRequest) (sessiontypes.Sessions, *query.PageResponse, error) {
 	var (
-		qc = sessiontypes.NewQueryServiceClient(c.ctx)
+		qc = sessiontypes.NewQueryServiceClient(c.conn())
 	)
 
 	res, err := qc.QuerySessionsForAddress(context.Background(),
 		sessiontypes.NewQuerySessionsForAddressRequest(address, status, pagination))
 	if err != nil {
-		return nil, utils.IsNotFoundError(err)
+		return nil, nil, utils.IsNotFoundError(err)
+	}
+
+	return res.Sessions, res.Pagination, nil
+}
+
+// QueryAllSessionsForNode returns the sessions of the node, paging through all
+// of them.
+func (c *Client) QueryAllSessionsForNode(address hubtypes.NodeAddress) (sessiontypes.Sessions, error) {
+	var (
+		items      sessiontypes.Sessions
+		pagination = &query.PageRequest{
+			Limit: 100,
+		}
+	)
+
+	for {
+		res, page, err := c.QuerySessionsForNode(address, pagination)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, res...)
+		if page == nil || len(page.NextKey) == 0 {
+			return items, nil
+		}
+
+		pagination.Key = page.NextKey
 	}
+}
 
-	return res.Sessions, nil
+// QueryAllSessionsForAddress returns the sessions of the address with the
+// status, paging through all of them.
+func (c *Client) QueryAllSessionsForAddress(address sdk.AccAddress, status hubtypes.Status) (sessiontypes.Sessions, error) {
+	var (
+		items      sessiontypes.Sessions
+		pagination = &query.PageRequest{
+			Limit: 100,
+		}
+	)
+
+	for {
+		res, page, err := c.QuerySessionsForAddress(address, status, pagination)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, res...)
+		if page == nil || len(page.NextKey) == 0 {
+			return items, nil
+		}
+
+		pagination.Key = page.NextKey
+	}
+}
+
+// QuerySessionForSubscription returns the active session of the address on the
+// subscription, or nil if there is none. The hub has no such query, so the
+// active sessions of the address are paged through instead.
+func (c *Client) QuerySessionForSubscription(id uint64, address sdk.AccAddress) (*sessiontypes.Session, error) {
+	pagination := &query.PageRequest{
+		Limit: 100,
+	}
+
+	for {
+		items, res, err := c.QuerySessionsForAddress(address, hubtypes.StatusActive, pagination)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			if items[i].Subscription == id {
+				return &items[i], nil
+			}
+		}
+
+		if res == nil || len(res.NextKey) == 0 {
+			return nil, nil
+		}
+
+		pagination.Key = res.NextKey
+	}
 }
@@ -0,0 +1,68 @@
+package lite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retryConn is the connection of the query clients. It retries a query that
+// failed to reach the chain, doubling the backoff between attempts, while an
+// error the chain answered with, such as not found, is returned right away.
+// Only queries may go through it, as those are safe to repeat.
+type retryConn struct {
+	ctx      client.Context
+	attempts uint64
+	backoff  time.Duration
+}
+
+func (c *Client) conn() *retryConn {
+	return &retryConn{
+		ctx:      c.ctx,
+		attempts: c.queryAttempts,
+		backoff:  c.queryBackoff,
+	}
+}
+
+// isTransientError tells whether a query failed on its way to the chain, on
+// a connection error, a timeout or an unavailable endpoint, rather than being
+// answered with an error.
+func isTransientError(err error) bool {
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.Unavailable || s.Code() == codes.DeadlineExceeded
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (c *retryConn) Invoke(ctx context.Context, method string, req, reply interface{}, opts ...grpc.CallOption) (err error) {
+	backoff := c.backoff
+	for attempt := uint64(1); ; attempt++ {
+		err = c.ctx.Invoke(ctx, method, req, reply, opts...)
+		if err == nil || attempt >= c.attempts || !isTransientError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+func (c *retryConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.ctx.NewStream(ctx, desc, method, opts...)
+}
@@ -0,0 +1,136 @@
+package lite
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+)
+
+const (
+	rpcDialTimeout     = 5 * time.Second
+	rpcResponseTimeout = 15 * time.Second
+	rpcCooldown        = 30 * time.Second
+)
+
+type rpcEndpoint struct {
+	url       *neturl.URL
+	downUntil time.Time
+}
+
+// failoverTransport sends each request to the first of its endpoints that
+// isn't cooling down after a failure, moving on to the next on a connection
+// error, a timeout or a gateway error. With every endpoint cooling down, all
+// of them are tried again in order.
+type failoverTransport struct {
+	mutex     sync.Mutex
+	base      http.RoundTripper
+	endpoints []*rpcEndpoint
+}
+
+func (t *failoverTransport) order() []int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var (
+		now  = time.Now()
+		up   []int
+		down []int
+	)
+
+	for i, endpoint := range t.endpoints {
+		if now.Before(endpoint.downUntil) {
+			down = append(down, i)
+		} else {
+			up = append(up, i)
+		}
+	}
+
+	return append(up, down...)
+}
+
+func (t *failoverTransport) mark(i int, ok bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if ok {
+		t.endpoints[i].downUntil = time.Time{}
+	} else {
+		t.endpoints[i].downUntil = time.Now().Add(rpcCooldown)
+	}
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	for _, i := range t.order() {
+		r := req.Clone(req.Context())
+		r.URL.Scheme = t.endpoints[i].url.Scheme
+		r.URL.Host = t.endpoints[i].url.Host
+		r.URL.Path = t.endpoints[i].url.Path + strings.TrimPrefix(req.URL.Path, t.endpoints[0].url.Path)
+		r.Host = ""
+
+		if req.GetBody != nil {
+			if r.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err = t.base.RoundTrip(r)
+		if err == nil {
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				_ = resp.Body.Close()
+				err = fmt.Errorf("rpc endpoint %s responded with status %d", t.endpoints[i].url.Host, resp.StatusCode)
+			default:
+				t.mark(i, true)
+				return resp, nil
+			}
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+
+		t.mark(i, false)
+	}
+
+	return nil, err
+}
+
+// NewRPCClient returns a Tendermint RPC client which fails over between the
+// addresses. The first one is preferred whenever it is reachable.
+func NewRPCClient(addresses ...string) (rpcclient.Client, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no rpc addresses")
+	}
+
+	transport := &failoverTransport{
+		base: &http.Transport{
+			// Set to true to prevent GZIP-bomb DoS attacks, as the Tendermint client does
+			DisableCompression: true,
+			DialContext: (&net.Dialer{
+				Timeout: rpcDialTimeout,
+			}).DialContext,
+			ResponseHeaderTimeout: rpcResponseTimeout,
+			TLSHandshakeTimeout:   rpcDialTimeout,
+		},
+	}
+
+	for _, address := range addresses {
+		url, err := neturl.Parse(address)
+		if err != nil {
+			return nil, err
+		}
+		if url.Scheme != "http" && url.Scheme != "https" {
+			return nil, fmt.Errorf("invalid rpc address %s; expected http or https scheme", address)
+		}
+
+		transport.endpoints = append(transport.endpoints, &rpcEndpoint{url: url})
+	}
+
+	return rpchttp.NewWithClient(addresses[0], "/websocket", &http.Client{Transport: transport})
+}
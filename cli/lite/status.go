@@ -0,0 +1,31 @@
+package lite
+
+import (
+	"context"
+	"time"
+)
+
+// A chain whose latest block is older than this is considered behind, even
+// when the node doesn't report that it is catching up.
+const staleBlockThreshold = time.Minute
+
+type SyncStatus struct {
+	LatestBlockHeight int64
+	LatestBlockTime   time.Time
+	CatchingUp        bool
+	Stale             bool
+}
+
+func (c *Client) SyncStatus() (*SyncStatus, error) {
+	res, err := c.ctx.Client.Status(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncStatus{
+		LatestBlockHeight: res.SyncInfo.LatestBlockHeight,
+		LatestBlockTime:   res.SyncInfo.LatestBlockTime,
+		CatchingUp:        res.SyncInfo.CatchingUp,
+		Stale:             time.Since(res.SyncInfo.LatestBlockTime) > staleBlockThreshold,
+	}, nil
+}
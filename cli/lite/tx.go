@@ -1,36 +1,76 @@
 package lite
 
 import (
-	"fmt"
-
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
-func (c *Client) BroadcastTx(memo string, messages ...sdk.Msg) (res *sdk.TxResponse, err error) {
+func (c *Client) txFactory(opts types.TxOptions) (tx.Factory, error) {
+	account, err := c.AccountRetriever().GetAccount(c.ctx, c.FromAddress())
+	if err != nil {
+		return tx.Factory{}, err
+	}
+
+	txf := c.txf.
+		WithAccountNumber(account.GetAccountNumber()).
+		WithMemo(opts.Memo).
+		WithSequence(account.GetSequence())
+
+	if opts.GasAdjustment > 0 {
+		txf = txf.WithGasAdjustment(opts.GasAdjustment)
+	}
+	if opts.GasPrices != "" {
+		txf = txf.WithGasPrices(opts.GasPrices)
+	}
+	if opts.Fees != "" {
+		txf = txf.WithFees(opts.Fees).WithGasPrices("")
+	}
+
+	return txf, nil
+}
+
+func (c *Client) simulate(txf tx.Factory, messages ...sdk.Msg) (uint64, error) {
+	_, adjusted, err := tx.CalculateGas(c.ctx.QueryWithData, txf, messages...)
+	if err != nil {
+		return 0, err
+	}
+
+	return adjusted, nil
+}
+
+func (c *Client) SimulateTx(opts types.TxOptions, messages ...sdk.Msg) (uint64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	account, err := c.AccountRetriever().GetAccount(c.ctx, c.FromAddress())
+	txf, err := c.txFactory(opts)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	var (
-		name = c.From()
-		txf  = c.txf.
-			WithAccountNumber(account.GetAccountNumber()).
-			WithMemo(memo).
-			WithSequence(account.GetSequence())
-	)
+	return c.simulate(txf, messages...)
+}
+
+func (c *Client) BroadcastTx(opts types.TxOptions, messages ...sdk.Msg) (res *sdk.TxResponse, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	txf, err := c.txFactory(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	if c.SimulateAndExecute() {
-		_, adjusted, err := tx.CalculateGas(c.ctx.QueryWithData, txf, messages...)
+	switch {
+	case opts.Gas > 0:
+		txf = txf.WithGas(opts.Gas)
+	case opts.Simulate || c.SimulateAndExecute():
+		gas, err := c.simulate(txf, messages...)
 		if err != nil {
 			return nil, err
 		}
 
-		txf = txf.WithGas(adjusted)
+		txf = txf.WithGas(gas)
 	}
 
 	txb, err := tx.BuildUnsignedTx(txf, messages...)
@@ -38,7 +78,7 @@ func (c *Client) BroadcastTx(memo string, messages ...sdk.Msg) (res *sdk.TxRespo
 		return nil, err
 	}
 
-	if err := tx.Sign(txf, name, txb, true); err != nil {
+	if err := tx.Sign(txf, c.From(), txb, true); err != nil {
 		return nil, err
 	}
 
@@ -52,7 +92,7 @@ func (c *Client) BroadcastTx(memo string, messages ...sdk.Msg) (res *sdk.TxRespo
 		return nil, err
 	}
 	if result.Code != 0 {
-		return nil, fmt.Errorf(result.RawLog)
+		return nil, types.NewTxError(result.Codespace, result.Code, result.RawLog)
 	}
 
 	return result, nil
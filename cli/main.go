@@ -13,16 +13,6 @@ import (
 	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
-const (
-	flagChainBroadcastMode      = "chain.broadcast-mode"
-	flagChainGasAdjustment      = "chain.gas-adjustment"
-	flagChainGas                = "chain.gas"
-	flagChainGasPrices          = "chain.gas-prices"
-	flagChainID                 = "chain.id"
-	flagChainRPCAddress         = "chain.rpc-address"
-	flagChainSimulateAndExecute = "chain.simulate-and-execute"
-)
-
 func main() {
 	log.SetOutput(os.Stdout)
 	sent.GetConfig().Seal()
@@ -62,27 +52,7 @@ func main() {
 					}
 				}
 
-				if viper.GetString(flagChainBroadcastMode) != defCfg.Chain.BroadcastMode {
-					cfg.Chain.BroadcastMode = viper.GetString(flagChainBroadcastMode)
-				}
-				if viper.GetFloat64(flagChainGasAdjustment) != defCfg.Chain.GasAdjustment {
-					cfg.Chain.GasAdjustment = viper.GetFloat64(flagChainGasAdjustment)
-				}
-				if viper.GetString(flagChainGasPrices) != defCfg.Chain.GasPrices {
-					cfg.Chain.GasPrices = viper.GetString(flagChainGasPrices)
-				}
-				if viper.GetUint64(flagChainGas) != defCfg.Chain.Gas {
-					cfg.Chain.Gas = viper.GetUint64(flagChainGas)
-				}
-				if viper.GetString(flagChainID) != defCfg.Chain.ID {
-					cfg.Chain.ID = viper.GetString(flagChainID)
-				}
-				if viper.GetString(flagChainRPCAddress) != defCfg.Chain.RPCAddress {
-					cfg.Chain.RPCAddress = viper.GetString(flagChainRPCAddress)
-				}
-				if viper.GetBool(flagChainSimulateAndExecute) != defCfg.Chain.SimulateAndExecute {
-					cfg.Chain.SimulateAndExecute = viper.GetBool(flagChainSimulateAndExecute)
-				}
+				cmd.ApplyRootFlags(cfg, defCfg)
 
 				return cfg.Validate()
 			},
@@ -90,22 +60,26 @@ func main() {
 	)
 
 	root.PersistentFlags().String(types.FlagHome, types.DefaultHomeDirectory, "")
-	root.PersistentFlags().String(flagChainBroadcastMode, defCfg.Chain.BroadcastMode, "")
-	root.PersistentFlags().Float64(flagChainGasAdjustment, defCfg.Chain.GasAdjustment, "")
-	root.PersistentFlags().String(flagChainGasPrices, defCfg.Chain.GasPrices, "")
-	root.PersistentFlags().Uint64(flagChainGas, defCfg.Chain.Gas, "")
-	root.PersistentFlags().String(flagChainID, defCfg.Chain.ID, "")
-	root.PersistentFlags().String(flagChainRPCAddress, defCfg.Chain.RPCAddress, "")
-	root.PersistentFlags().Bool(flagChainSimulateAndExecute, defCfg.Chain.SimulateAndExecute, "")
+	root.PersistentFlags().String(cmd.FlagChainBroadcastMode, defCfg.Chain.BroadcastMode, "")
+	root.PersistentFlags().Float64(cmd.FlagChainGasAdjustment, defCfg.Chain.GasAdjustment, "")
+	root.PersistentFlags().String(cmd.FlagChainGasPrices, defCfg.Chain.GasPrices, "")
+	root.PersistentFlags().Uint64(cmd.FlagChainGas, defCfg.Chain.Gas, "")
+	root.PersistentFlags().String(cmd.FlagChainID, defCfg.Chain.ID, "")
+	root.PersistentFlags().String(cmd.FlagChainRPCAddress, defCfg.Chain.RPCAddress, "")
+	root.PersistentFlags().StringSlice(cmd.FlagChainRPCFallbacks, defCfg.Chain.RPCFallbackAddresses, "")
+	root.PersistentFlags().Bool(cmd.FlagChainSimulateAndExecute, defCfg.Chain.SimulateAndExecute, "")
+	root.PersistentFlags().Uint64(cmd.FlagNodeTimeout, defCfg.Node.Timeout, "")
 
 	_ = viper.BindPFlag(types.FlagHome, root.PersistentFlags().Lookup(types.FlagHome))
-	_ = viper.BindPFlag(flagChainBroadcastMode, root.PersistentFlags().Lookup(flagChainBroadcastMode))
-	_ = viper.BindPFlag(flagChainGasAdjustment, root.PersistentFlags().Lookup(flagChainGasAdjustment))
-	_ = viper.BindPFlag(flagChainGasPrices, root.PersistentFlags().Lookup(flagChainGasPrices))
-	_ = viper.BindPFlag(flagChainGas, root.PersistentFlags().Lookup(flagChainGas))
-	_ = viper.BindPFlag(flagChainID, root.PersistentFlags().Lookup(flagChainID))
-	_ = viper.BindPFlag(flagChainRPCAddress, root.PersistentFlags().Lookup(flagChainRPCAddress))
-	_ = viper.BindPFlag(flagChainSimulateAndExecute, root.PersistentFlags().Lookup(flagChainSimulateAndExecute))
+	_ = viper.BindPFlag(cmd.FlagChainBroadcastMode, root.PersistentFlags().Lookup(cmd.FlagChainBroadcastMode))
+	_ = viper.BindPFlag(cmd.FlagChainGasAdjustment, root.PersistentFlags().Lookup(cmd.FlagChainGasAdjustment))
+	_ = viper.BindPFlag(cmd.FlagChainGasPrices, root.PersistentFlags().Lookup(cmd.FlagChainGasPrices))
+	_ = viper.BindPFlag(cmd.FlagChainGas, root.PersistentFlags().Lookup(cmd.FlagChainGas))
+	_ = viper.BindPFlag(cmd.FlagChainID, root.PersistentFlags().Lookup(cmd.FlagChainID))
+	_ = viper.BindPFlag(cmd.FlagChainRPCAddress, root.PersistentFlags().Lookup(cmd.FlagChainRPCAddress))
+	_ = viper.BindPFlag(cmd.FlagChainRPCFallbacks, root.PersistentFlags().Lookup(cmd.FlagChainRPCFallbacks))
+	_ = viper.BindPFlag(cmd.FlagChainSimulateAndExecute, root.PersistentFlags().Lookup(cmd.FlagChainSimulateAndExecute))
+	_ = viper.BindPFlag(cmd.FlagNodeTimeout, root.PersistentFlags().Lookup(cmd.FlagNodeTimeout))
 
 	root.AddCommand(
 		cmd.ServerCmd(cfg),
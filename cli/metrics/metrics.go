@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+)
+
+const (
+	namespace = "sentinel_client"
+)
+
+var (
+	sessionAdds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "session_add_total",
+			Help:      "Number of session add requests by result.",
+		},
+		[]string{"result"},
+	)
+	nodeRequestDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "node_request_duration_seconds",
+			Help:      "Latency of session requests sent to nodes.",
+			Buckets:   prometheus.DefBuckets,
+		},
+	)
+)
+
+func ObserveSessionAdd(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+
+	sessionAdds.WithLabelValues(result).Inc()
+}
+
+func ObserveNodeRequest(start time.Time) {
+	nodeRequestDuration.Observe(time.Since(start).Seconds())
+}
+
+type sessionCollector struct {
+	ctx      *context.Context
+	active   *prometheus.Desc
+	download *prometheus.Desc
+	upload   *prometheus.Desc
+}
+
+func newSessionCollector(ctx *context.Context) *sessionCollector {
+	return &sessionCollector{
+		ctx: ctx,
+		active: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_sessions"),
+			"Number of active sessions.", nil, nil),
+		download: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "download_bytes_total"),
+			"Bytes downloaded through the interface.", []string{"interface"}, nil),
+		upload: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "upload_bytes_total"),
+			"Bytes uploaded through the interface.", []string{"interface"}, nil),
+	}
+}
+
+func (c *sessionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.active
+	ch <- c.download
+	ch <- c.upload
+}
+
+func (c *sessionCollector) Collect(ch chan<- prometheus.Metric) {
+	ids := c.ctx.ServiceIDs()
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(len(ids)))
+
+	for _, id := range ids {
+		wg, ok := c.ctx.Service(id).(*wireguard.WireGuard)
+		if !ok {
+			continue
+		}
+
+		download, upload, err := wg.Transfer()
+		if err != nil {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.download, prometheus.CounterValue, float64(download), wg.Config().Name)
+		ch <- prometheus.MustNewConstMetric(c.upload, prometheus.CounterValue, float64(upload), wg.Config().Name)
+	}
+}
+
+func Register(ctx *context.Context) error {
+	for _, collector := range []prometheus.Collector{
+		sessionAdds,
+		nodeRequestDuration,
+		newSessionCollector(ctx),
+	} {
+		if err := prometheus.Register(collector); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
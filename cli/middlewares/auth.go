@@ -1,7 +1,9 @@
 package middlewares
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -9,10 +11,20 @@ import (
 	"github.com/sentinel-official/desktop-client/cli/utils"
 )
 
+func isReadOnly(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+}
+
 func TokenVerify(ctx *context.Context) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if ctx.Token() != r.Header.Get("Authorization") {
+			if !ctx.Config().Auth.ProtectReads && isReadOnly(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(ctx.Token()), []byte(token)) != 1 {
 				utils.WriteErrorToResponse(w, http.StatusUnauthorized, -1, "token mismatch")
 				return
 			}
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+// LimitBody rejects request bodies larger than server->max_body_size with 413.
+// The body is read here, so that handlers keep decoding it as before and don't
+// have to tell a body that is too large apart from a malformed one.
+func LimitBody(ctx *context.Context) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isReadOnly(r) || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limit := int64(ctx.Config().Server.MaxBodySize)
+			if r.ContentLength > limit {
+				utils.WriteErrorToResponse(w, http.StatusRequestEntityTooLarge, -1, "request body too large")
+				return
+			}
+
+			data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+			if err != nil {
+				if int64(len(data)) >= limit {
+					utils.WriteErrorToResponse(w, http.StatusRequestEntityTooLarge, -1, "request body too large")
+					return
+				}
+
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, -1, err.Error())
+				return
+			}
+
+			r.Body = ioutil.NopCloser(bytes.NewReader(data))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
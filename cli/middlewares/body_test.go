@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+// zeros yields zero bytes without holding them, so that an oversized body
+// costs no memory unless it is read whole.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
+
+func TestLimitBody(t *testing.T) {
+	const limit = 1 << 10
+
+	cfg := types.NewConfig()
+	cfg.Server.MaxBodySize = limit
+
+	tests := []struct {
+		name          string
+		method        string
+		size          int64
+		contentLength bool
+		status        int
+	}{
+		{"within the limit", http.MethodPost, limit, true, http.StatusOK},
+		{"within the limit without a length", http.MethodPost, limit, false, http.StatusOK},
+		{"over the limit", http.MethodPost, limit + 1, true, http.StatusRequestEntityTooLarge},
+		{"over the limit without a length", http.MethodPost, 1 << 30, false, http.StatusRequestEntityTooLarge},
+		{"read only", http.MethodGet, limit + 1, true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var read int64
+			handler := LimitBody(context.NewContext().WithConfig(cfg))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				read, _ = io.Copy(ioutil.Discard, r.Body)
+			}))
+
+			body := io.LimitReader(zeros{}, tt.size)
+			if tt.contentLength {
+				data, _ := ioutil.ReadAll(body)
+				body = bytes.NewReader(data)
+			}
+
+			r := httptest.NewRequest(tt.method, "/", body)
+			if !tt.contentLength {
+				r.ContentLength = -1
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status == http.StatusOK && read != tt.size {
+				t.Fatalf("expected the handler to read %d bytes, got %d", tt.size, read)
+			}
+		})
+	}
+}
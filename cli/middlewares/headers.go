@@ -2,11 +2,13 @@ package middlewares
 
 import (
 	"net/http"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
 func AddHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Content-Type", "application/json")
+		w.Header().Set("Content-Type", types.ContentTypeJSON)
 		next.ServeHTTP(w, r)
 	})
 }
@@ -1,21 +1,74 @@
 package middlewares
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
 	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
-func Log(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		rw := types.NewResponseWriter(w)
-		start := time.Now()
+type logEntry struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	Length    int       `json:"length"`
+	Latency   float64   `json:"latency_ms"`
+	ErrorCode int       `json:"error_code,omitempty"`
+	Remote    string    `json:"remote"`
+	UserAgent string    `json:"user_agent"`
+}
+
+func Log(ctx *context.Context) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := types.NewResponseWriter(w)
+			start := time.Now()
+
+			next.ServeHTTP(rw, r)
+
+			status := rw.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			level := types.LogLevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = types.LogLevelError
+			case status >= http.StatusBadRequest:
+				level = types.LogLevelWarn
+			}
+
+			if !types.IsLogLevelEnabled(level, ctx.Config().Log.Level) {
+				return
+			}
 
-		next.ServeHTTP(rw, r)
+			data, err := json.Marshal(
+				logEntry{
+					Time:      start.UTC(),
+					Level:     level,
+					Method:    r.Method,
+					Path:      r.URL.Path,
+					Status:    status,
+					Length:    rw.Length,
+					Latency:   float64(time.Since(start).Microseconds()) / 1e3,
+					ErrorCode: rw.ErrorCode,
+					Remote:    r.RemoteAddr,
+					UserAgent: r.UserAgent(),
+				},
+			)
+			if err != nil {
+				return
+			}
 
-		log.Printf("- %s - %s %s %s - %d %d - %s - %s - %s", r.RemoteAddr, r.Proto, r.Method, r.RequestURI,
-			rw.Status, rw.Length, time.Since(start), r.Referer(), r.UserAgent())
-	})
+			_, _ = log.Writer().Write(append(data, '\n'))
+		})
+	}
 }
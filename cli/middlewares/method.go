@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+var (
+	methods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodDelete,
+	}
+)
+
+// MethodNotAllowed handles requests whose path is routed, but not for their
+// method. OPTIONS is answered with the allowed methods; any other method gets
+// 405. The router does not run its middlewares for this handler.
+func MethodNotAllowed(router *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed := []string{http.MethodOptions}
+		for _, method := range methods {
+			var (
+				match mux.RouteMatch
+				req   = r.Clone(r.Context())
+			)
+
+			req.Method = method
+			if router.Match(req, &match) && match.MatchErr == nil {
+				allowed = append(allowed, method)
+			}
+		}
+
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		utils.WriteErrorToResponse(w, http.StatusMethodNotAllowed, -1, "method not allowed")
+	})
+}
@@ -5,11 +5,58 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/gorilla/mux"
 
 	"github.com/sentinel-official/desktop-client/cli/context"
 	"github.com/sentinel-official/desktop-client/cli/utils"
+	"github.com/sentinel-official/desktop-client/cli/x/common"
 )
 
+func HandlerGetBalance(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars  = mux.Vars(r)
+			denom = r.URL.Query().Get("denom")
+		)
+
+		address, err := sdk.AccAddressFromBech32(vars["address"])
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+
+		if denom != "" {
+			if err := sdk.ValidateDenom(denom); err != nil {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+				return
+			}
+
+			balance, err := ctx.Client().QueryBalance(address, denom)
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+				return
+			}
+
+			items := common.Coins{}
+			if balance != nil {
+				items = append(items, common.NewCoinFromRaw(balance))
+			}
+
+			utils.WriteResultToResponse(w, http.StatusOK, items)
+			return
+		}
+
+		balances, err := ctx.Client().QueryBalances(address)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			return
+		}
+
+		items := common.NewCoinsFromRaw(balances)
+		utils.WriteResultToResponse(w, http.StatusOK, items)
+	}
+}
+
 func HandlerSend(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		body, err := NewRequestSend(r)
@@ -36,9 +83,9 @@ func HandlerSend(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1004, err)
 			return
 		}
 
@@ -6,11 +6,12 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/sentinel-official/desktop-client/cli/types"
 	"github.com/sentinel-official/desktop-client/cli/x/common"
 )
 
 type RequestSend struct {
-	Memo  string       `json:"memo"`
+	types.TxOptions
 	To    string       `json:"to"`
 	Coins common.Coins `json:"coins"`
 }
@@ -25,6 +26,9 @@ func NewRequestSend(r *http.Request) (*RequestSend, error) {
 }
 
 func (r *RequestSend) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if _, err := sdk.AccAddressFromBech32(r.To); err != nil {
 		return err
 	}
@@ -9,6 +9,9 @@ import (
 )
 
 func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("GetBalance").
+		Methods(http.MethodGet).Path("/accounts/{address}/balance").
+		HandlerFunc(HandlerGetBalance(ctx))
 	r.Name("Send").
 		Methods(http.MethodPost).Path("/bank/send").
 		HandlerFunc(HandlerSend(ctx))
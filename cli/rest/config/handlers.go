@@ -3,16 +3,19 @@ package config
 import (
 	"net/http"
 	"path/filepath"
+	"strings"
 
-	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
 
 	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/lite"
 	"github.com/sentinel-official/desktop-client/cli/utils"
 )
 
 func HandlerGetConfig(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		utils.WriteResultToResponse(w, http.StatusOK, ctx.Config())
+		utils.WriteResultToResponse(w, http.StatusOK, ctx.Config().Redacted())
 	}
 }
 
@@ -28,72 +31,40 @@ func HandlerUpdateConfig(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
+		// The new client is built from a copy of the config, and neither is
+		// swapped in until the config is saved.
 		var (
-			cfg    = ctx.Config().Copy()
-			client = ctx.Client().Copy()
+			current = ctx.Config()
+			cfg     = current.Copy()
 		)
 
-		if body.Setup != cfg.Setup {
-			cfg.Setup = body.Setup
-		}
-		if body.From != "" && body.From != client.From() {
-			info, err := client.Keyring().Key(body.From)
+		cfg.Setup = body.Setup
+		cfg.Chain.BroadcastMode = body.Chain.BroadcastMode
+		cfg.Chain.GasAdjustment = body.Chain.GasAdjustment
+		cfg.Chain.GasPrices = body.Chain.GasPrices
+		cfg.Chain.Gas = body.Chain.Gas
+		cfg.Chain.ID = body.Chain.ID
+		cfg.Chain.RPCAddress = body.Chain.RPCAddress
+		cfg.Chain.SimulateAndExecute = body.Chain.SimulateAndExecute
+
+		// The keys are the same whether the keyring is locked or not, so the
+		// one of the current client is good for the lookup.
+		var info keyring.Info
+		if body.From != "" && body.From != ctx.Client().From() {
+			info, err = ctx.Client().Keyring().Key(body.From)
 			if err != nil {
 				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
 				return
 			}
-
-			client.WithFrom(body.From).
-				WithFromName(body.From).
-				WithFromAddress(info.GetAddress())
-		}
-		if body.Chain.BroadcastMode != cfg.Chain.BroadcastMode {
-			cfg.Chain.BroadcastMode = body.Chain.BroadcastMode
-			client.WithBroadcastMode(body.Chain.BroadcastMode)
-		}
-		if body.Chain.GasAdjustment != cfg.Chain.GasAdjustment {
-			cfg.Chain.GasAdjustment = body.Chain.GasAdjustment
-			client.WithGasAdjustment(body.Chain.GasAdjustment)
 		}
-		if body.Chain.GasPrices != cfg.Chain.GasPrices {
-			cfg.Chain.GasPrices = body.Chain.GasPrices
-			client.WithGasPrices(body.Chain.GasPrices)
-		}
-		if body.Chain.Gas != cfg.Chain.Gas {
-			cfg.Chain.Gas = body.Chain.Gas
-			client.WithGas(body.Chain.Gas)
-		}
-		if body.Chain.ID != cfg.Chain.ID && body.Chain.RPCAddress != cfg.Chain.RPCAddress {
-			rpcclient, err := rpchttp.New(body.Chain.RPCAddress, "/websocket")
-			if err != nil {
-				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
-				return
-			}
 
-			cfg.Chain.ID = body.Chain.ID
-			cfg.Chain.RPCAddress = body.Chain.RPCAddress
-			client.WithNodeURI(body.Chain.RPCAddress).
-				WithClient(rpcclient).
-				WithChainID(body.Chain.ID)
-		}
-		if body.Chain.ID != cfg.Chain.ID {
-			cfg.Chain.ID = body.Chain.ID
-			client.WithChainID(body.Chain.ID)
-		}
-		if body.Chain.RPCAddress != cfg.Chain.RPCAddress {
-			rpcclient, err := rpchttp.New(body.Chain.RPCAddress, "/websocket")
+		var rpc rpcclient.Client
+		if strings.Join(cfg.RPCAddresses(), ",") != strings.Join(current.RPCAddresses(), ",") {
+			rpc, err = lite.NewRPCClient(cfg.RPCAddresses()...)
 			if err != nil {
 				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
 				return
 			}
-
-			cfg.Chain.RPCAddress = body.Chain.RPCAddress
-			client.WithNodeURI(body.Chain.RPCAddress).
-				WithClient(rpcclient)
-		}
-		if body.Chain.SimulateAndExecute != cfg.Chain.SimulateAndExecute {
-			cfg.Chain.SimulateAndExecute = body.Chain.SimulateAndExecute
-			client.WithSimulateAndExecute(body.Chain.SimulateAndExecute)
 		}
 
 		if err := cfg.SaveToPath(filepath.Join(ctx.Home(), "config.toml")); err != nil {
@@ -101,9 +72,27 @@ func HandlerUpdateConfig(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		ctx.WithConfig(cfg.Copy()).
-			WithClient(client.Copy())
+		ctx.WithConfigAndClient(cfg, func(v *lite.Client) *lite.Client {
+			client := v.Copy().
+				WithBroadcastMode(cfg.Chain.BroadcastMode).
+				WithChainID(cfg.Chain.ID).
+				WithGas(cfg.Chain.Gas).
+				WithGasAdjustment(cfg.Chain.GasAdjustment).
+				WithGasPrices(cfg.Chain.GasPrices).
+				WithSimulateAndExecute(cfg.Chain.SimulateAndExecute)
+			if info != nil {
+				client.WithFrom(body.From).
+					WithFromName(body.From).
+					WithFromAddress(info.GetAddress())
+			}
+			if rpc != nil {
+				client.WithNodeURI(cfg.Chain.RPCAddress).
+					WithClient(rpc)
+			}
+
+			return client
+		})
 
-		utils.WriteResultToResponse(w, http.StatusOK, cfg)
+		utils.WriteResultToResponse(w, http.StatusOK, cfg.Redacted())
 	}
 }
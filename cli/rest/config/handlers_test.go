@@ -0,0 +1,62 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/lite"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func TestHandlerUpdateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		home    func(t *testing.T) string
+		status  int
+		swapped bool
+	}{
+		{"saved", func(t *testing.T) string { return t.TempDir() }, http.StatusOK, true},
+		{"save failed", func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing") }, http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := types.NewConfig().WithDefaultValues()
+			cfg.Chain.ID = "old-chain"
+
+			var (
+				client = lite.NewDefaultClient().WithChainID(cfg.Chain.ID)
+				ctx    = context.NewContext().
+					WithHome(tt.home(t)).
+					WithConfig(cfg).
+					WithClient(client)
+				body = `{"chain":{"broadcast_mode":"sync","id":"new-chain","rpc_address":"` + cfg.Chain.RPCAddress + `"}}`
+				w    = httptest.NewRecorder()
+				r    = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+			)
+
+			HandlerUpdateConfig(ctx).ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+
+			want := "old-chain"
+			if tt.swapped {
+				want = "new-chain"
+			}
+			if v := ctx.Config().Chain.ID; v != want {
+				t.Fatalf("expected config chain %s, got %s", want, v)
+			}
+			if v := ctx.Client().ChainID(); v != want {
+				t.Fatalf("expected client chain %s, got %s", want, v)
+			}
+			if v := client.ChainID(); v != "old-chain" {
+				t.Fatalf("expected the previous client untouched, got chain %s", v)
+			}
+		})
+	}
+}
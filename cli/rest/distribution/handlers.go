@@ -56,9 +56,9 @@ func HandlerWithdrawRewards(ctx *context.Context) http.HandlerFunc {
 			messages = append(messages, message)
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, messages...)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, messages...)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1006, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1006, err)
 			return
 		}
 
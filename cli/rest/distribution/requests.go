@@ -6,10 +6,12 @@ import (
 	"net/http"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
 type RequestWithdrawRewards struct {
-	Memo       string   `json:"memo"`
+	types.TxOptions
 	Validators []string `json:"validators"`
 }
 
@@ -23,6 +25,9 @@ func NewRequestWithdrawRewards(r *http.Request) (*RequestWithdrawRewards, error)
 }
 
 func (r *RequestWithdrawRewards) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if len(r.Validators) == 0 {
 		return fmt.Errorf("invalid validators length; expected length is more than 0")
 	}
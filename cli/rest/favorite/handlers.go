@@ -0,0 +1,109 @@
+package favorite
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	hubtypes "github.com/sentinel-official/hub/types"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+func HandlerGetFavorites(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		items, err := loadFavorites(ctx.Home())
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, sortedFavorites(items))
+	}
+}
+
+// HandlerAddFavorite saves a node as a favorite, or updates its label when it
+// is one already.
+func HandlerAddFavorite(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := NewRequestAddFavorite(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		address, _ := hubtypes.NodeAddressFromBech32(body.Address)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		items, err := loadFavorites(ctx.Home())
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			return
+		}
+
+		item, ok := items[address.String()]
+		if !ok {
+			item.CreatedAt = time.Now().UTC()
+		}
+
+		item.Label = body.Label
+		items[address.String()] = item
+
+		if err := saveFavorites(ctx.Home(), items); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			return
+		}
+
+		status := http.StatusCreated
+		if ok {
+			status = http.StatusOK
+		}
+
+		utils.WriteResultToResponse(w, status, NewResponseFavorite(address.String(), item))
+	}
+}
+
+func HandlerDeleteFavorite(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
+		)
+
+		address, err := hubtypes.NodeAddressFromBech32(vars["address"])
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		items, err := loadFavorites(ctx.Home())
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
+			return
+		}
+		if _, ok := items[address.String()]; !ok {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1003, "favorite does not exist")
+			return
+		}
+
+		delete(items, address.String())
+		if err := saveFavorites(ctx.Home(), items); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, nil)
+	}
+}
@@ -0,0 +1,39 @@
+package favorite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
+	hubtypes "github.com/sentinel-official/hub/types"
+)
+
+const (
+	maxLabelLength = 64
+)
+
+type RequestAddFavorite struct {
+	Address string `json:"address"`
+	Label   string `json:"label"`
+}
+
+func NewRequestAddFavorite(r *http.Request) (*RequestAddFavorite, error) {
+	var body RequestAddFavorite
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestAddFavorite) Validate() error {
+	if _, err := hubtypes.NodeAddressFromBech32(r.Address); err != nil {
+		return fmt.Errorf("invalid field Address")
+	}
+	if utf8.RuneCountInString(r.Label) > maxLabelLength {
+		return fmt.Errorf("invalid field Label; expected at most %d characters", maxLabelLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package favorite
+
+import (
+	"time"
+)
+
+type ResponseFavorite struct {
+	Address   string    `json:"address"`
+	Label     string    `json:"label"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewResponseFavorite(address string, v favorite) ResponseFavorite {
+	return ResponseFavorite{
+		Address:   address,
+		Label:     v.Label,
+		CreatedAt: v.CreatedAt,
+	}
+}
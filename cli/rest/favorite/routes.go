@@ -0,0 +1,21 @@
+package favorite
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("GetFavorites").
+		Methods(http.MethodGet, http.MethodHead).Path("/favorites").
+		HandlerFunc(HandlerGetFavorites(ctx))
+	r.Name("AddFavorite").
+		Methods(http.MethodPost).Path("/favorites").
+		HandlerFunc(HandlerAddFavorite(ctx))
+	r.Name("DeleteFavorite").
+		Methods(http.MethodDelete).Path("/favorites/{address}").
+		HandlerFunc(HandlerDeleteFavorite(ctx))
+}
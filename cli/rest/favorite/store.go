@@ -0,0 +1,94 @@
+package favorite
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	favoritesFileName = "favorites.json"
+)
+
+// mutex serialises the reads and writes of the favorites file, so that
+// concurrent requests do not lose each other's changes.
+var mutex sync.Mutex
+
+type favorite struct {
+	Label     string    `json:"label"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// loadFavorites returns the favorites kept under the home directory, keyed by
+// node address.
+func loadFavorites(home string) (map[string]favorite, error) {
+	data, err := ioutil.ReadFile(filepath.Join(home, favoritesFileName))
+	if os.IsNotExist(err) {
+		return map[string]favorite{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]favorite)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func saveFavorites(home string, items map[string]favorite) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	// The file is written next to the favorites and renamed over them, so a
+	// crash mid write leaves the previous ones in place.
+	file, err := ioutil.TempFile(home, favoritesFileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filepath.Join(home, favoritesFileName))
+}
+
+// sortedFavorites returns the favorites sorted by label, and by address for
+// equal labels.
+func sortedFavorites(items map[string]favorite) []ResponseFavorite {
+	list := make([]ResponseFavorite, 0, len(items))
+	for address, item := range items {
+		list = append(list, NewResponseFavorite(address, item))
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Label != list[j].Label {
+			return list[i].Label < list[j].Label
+		}
+
+		return list[i].Address < list[j].Address
+	})
+
+	return list
+}
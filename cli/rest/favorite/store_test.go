@@ -0,0 +1,40 @@
+package favorite
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestSaveFavorites(t *testing.T) {
+	home := t.TempDir()
+
+	for _, items := range []map[string]favorite{
+		{"sentnode1a": {Label: "first"}},
+		{"sentnode1a": {Label: "first"}, "sentnode1b": {Label: "second"}},
+	} {
+		if err := saveFavorites(home, items); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := loadFavorites(home)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) != len(items) {
+			t.Fatalf("expected %d favorites, got %d", len(items), len(loaded))
+		}
+		for address, item := range items {
+			if loaded[address].Label != item.Label {
+				t.Fatalf("expected label %q for %s, got %q", item.Label, address, loaded[address].Label)
+			}
+		}
+	}
+
+	entries, err := ioutil.ReadDir(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != favoritesFileName {
+		t.Fatalf("expected only %s to be left, got %d files", favoritesFileName, len(entries))
+	}
+}
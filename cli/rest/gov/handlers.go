@@ -95,9 +95,9 @@ func HandlerVote(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1005, err)
 			return
 		}
 
@@ -5,10 +5,12 @@ import (
 	"net/http"
 
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
 type RequestVote struct {
-	Memo   string `json:"memo"`
+	types.TxOptions
 	Option string `json:"option"`
 }
 
@@ -22,6 +24,9 @@ func NewRequestVote(r *http.Request) (*RequestVote, error) {
 }
 
 func (r *RequestVote) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if _, err := govtypes.VoteOptionFromString(r.Option); err != nil {
 		return err
 	}
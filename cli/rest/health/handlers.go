@@ -0,0 +1,40 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	clientcontext "github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+const (
+	statusTimeout = 3 * time.Second
+)
+
+func HandlerHealth(ctx *clientcontext.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, cancel := context.WithTimeout(r.Context(), statusTimeout)
+		defer cancel()
+
+		res, err := ctx.Client().Client().Status(c)
+		if err != nil {
+			utils.WriteResultToResponse(w, http.StatusServiceUnavailable,
+				ResponseHealth{
+					Status:         "unavailable",
+					ChainReachable: false,
+				},
+			)
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK,
+			ResponseHealth{
+				Status:            "ok",
+				ChainReachable:    true,
+				LatestBlockHeight: res.SyncInfo.LatestBlockHeight,
+			},
+		)
+	}
+}
@@ -0,0 +1,7 @@
+package health
+
+type ResponseHealth struct {
+	Status            string `json:"status"`
+	ChainReachable    bool   `json:"chain_reachable"`
+	LatestBlockHeight int64  `json:"latest_block_height"`
+}
@@ -0,0 +1,15 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("Health").
+		Methods(http.MethodGet).Path("/health").
+		HandlerFunc(HandlerHealth(ctx))
+}
@@ -0,0 +1,112 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+func HandlerListInterfaces(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		paths, err := filepath.Glob(filepath.Join(ctx.Home(), "*.conf"))
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			return
+		}
+
+		items := make([]ResponseInterface, 0, len(paths))
+		for _, path := range paths {
+			cfg, err := wgt.NewConfigFromFile(path)
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
+				return
+			}
+
+			addresses := make([]string, 0, len(cfg.Interface.Addresses))
+			for _, address := range cfg.Interface.Addresses {
+				addresses = append(addresses, address.String())
+			}
+
+			items = append(items, ResponseInterface{
+				Name:       cfg.Name,
+				Addresses:  addresses,
+				ListenPort: cfg.Interface.ListenPort,
+				Running: wireguard.NewWireGuard().
+					WithConfig(cfg).
+					WithConfigDir(ctx.Home()).
+					IsUp(),
+			})
+
+			cfg.Interface.PrivateKey.Zero()
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, items)
+	}
+}
+
+func HandlerGetInterfaceStats(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		if err := wgt.ValidateInterfaceName(vars["name"]); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+
+		cfg, err := wgt.NewConfigFromFile(filepath.Join(ctx.Home(), fmt.Sprintf("%s.conf", vars["name"])))
+		if err != nil {
+			if os.IsNotExist(err) {
+				utils.WriteErrorToResponse(w, http.StatusNotFound, 1002, "interface does not exist")
+				return
+			}
+
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			return
+		}
+
+		// The private key is read along with the config but never used here.
+		defer cfg.Interface.PrivateKey.Zero()
+
+		wg := wireguard.NewWireGuard().
+			WithConfig(cfg).
+			WithConfigDir(ctx.Home())
+		if !wg.IsUp() {
+			utils.WriteErrorToResponse(w, http.StatusConflict, 1004, "interface is not running")
+			return
+		}
+
+		dump, err := wg.Dump()
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
+			return
+		}
+
+		item := ResponseInterfaceStats{
+			Name:       cfg.Name,
+			PublicKey:  dump.PublicKey,
+			ListenPort: dump.ListenPort,
+			Peers:      make([]ResponsePeerStats, 0, len(dump.Peers)),
+		}
+		for _, peer := range dump.Peers {
+			item.Peers = append(item.Peers, ResponsePeerStats{
+				PublicKey:           peer.PublicKey,
+				Endpoint:            peer.Endpoint,
+				AllowedIPs:          peer.AllowedIPs,
+				LatestHandshake:     peer.LatestHandshake,
+				Download:            peer.Download,
+				Upload:              peer.Upload,
+				PersistentKeepalive: peer.PersistentKeepalive,
+			})
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"time"
+)
+
+type ResponseInterface struct {
+	Name       string   `json:"name"`
+	Addresses  []string `json:"addresses"`
+	ListenPort uint16   `json:"listen_port"`
+	Running    bool     `json:"running"`
+}
+
+type ResponsePeerStats struct {
+	PublicKey           string    `json:"public_key"`
+	Endpoint            string    `json:"endpoint,omitempty"`
+	AllowedIPs          []string  `json:"allowed_ips"`
+	LatestHandshake     time.Time `json:"latest_handshake"`
+	Download            int64     `json:"download"`
+	Upload              int64     `json:"upload"`
+	PersistentKeepalive uint64    `json:"persistent_keepalive,omitempty"`
+}
+
+type ResponseInterfaceStats struct {
+	Name       string              `json:"name"`
+	PublicKey  string              `json:"public_key"`
+	ListenPort uint16              `json:"listen_port"`
+	Peers      []ResponsePeerStats `json:"peers"`
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("ListInterfaces").
+		Methods(http.MethodGet).Path("/interfaces").
+		HandlerFunc(HandlerListInterfaces(ctx))
+	r.Name("GetInterfaceStats").
+		Methods(http.MethodGet).Path("/interfaces/{name}/stats").
+		HandlerFunc(HandlerGetInterfaceStats(ctx))
+}
@@ -2,7 +2,9 @@ package keys
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -26,7 +28,7 @@ func HandlerGetKey(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		item := common.NewKeyFromRaw(info, "")
+		item := common.NewKeyFromRaw(info)
 		utils.WriteResultToResponse(w, http.StatusOK, item)
 	}
 }
@@ -39,7 +41,7 @@ func HandlerGetKeys(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		items := common.NewKeysFromRaw(infos, nil)
+		items := common.NewKeysFromRaw(infos)
 		utils.WriteResultToResponse(w, http.StatusOK, items)
 	}
 }
@@ -67,7 +69,10 @@ func HandlerAddKey(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
+		var generated bool
 		if body.Mnemonic == "" {
+			generated = true
+
 			entropy, err := bip39.NewEntropy(256)
 			if err != nil {
 				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
@@ -93,19 +98,197 @@ func HandlerAddKey(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		item := common.NewKeyFromRaw(info, body.Mnemonic)
+		// The mnemonic is only returned when it was generated here, as the
+		// caller has no other way to back up the key.
+		item := ResponseAddKey{
+			Key: common.NewKeyFromRaw(info),
+		}
+		if generated {
+			item.Mnemonic = body.Mnemonic
+		}
+
+		utils.WriteResultToResponse(w, http.StatusCreated, item)
+	}
+}
+
+func HandlerImportKey(ctx *context.Context) http.HandlerFunc {
+	algorithms, _ := ctx.Client().Keyring().SupportedAlgorithms()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := NewRequestImportKey(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		info, _ := ctx.Client().Keyring().Key(body.Name)
+		if info != nil {
+			utils.WriteErrorToResponse(w, http.StatusConflict, 1003, "key already exists")
+			return
+		}
+
+		path := body.HDPath
+		if path == "" {
+			path = hd.CreateHDPath(sdk.GetConfig().GetCoinType(), body.Account, body.Index).String()
+		}
+
+		algorithm, err := keyring.NewSigningAlgoFromString(string(hd.Secp256k1Type), algorithms)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			return
+		}
+
+		info, err = ctx.Client().Keyring().NewAccount(body.Name, body.Mnemonic, body.BIP39Password, path, algorithm)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
+			return
+		}
+
+		item := common.NewKeyFromRaw(info)
 		utils.WriteResultToResponse(w, http.StatusCreated, item)
 	}
 }
 
+func HandlerExportKey(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
+		)
+
+		body, err := NewRequestExportKey(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		if _, err := ctx.Client().Keyring().Key(vars["name"]); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1003, err.Error())
+			return
+		}
+
+		kr, err := unlockedKeyring(ctx, body.KeyringPassphrase)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusUnauthorized, 1004, err.Error())
+			return
+		}
+
+		armor, err := kr.ExportPrivKeyArmor(vars["name"], body.Passphrase)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, ResponseExportKey{Armor: armor})
+	}
+}
+
+func HandlerUnlockKeyring(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := NewRequestUnlockKeyring(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		if ctx.Config().Keyring.Backend != keyring.BackendFile {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1003, "unlock is only supported by the file backend")
+			return
+		}
+
+		kr, err := unlockedKeyring(ctx, body.Passphrase)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			return
+		}
+
+		// Listing reads every key, which fails if the passphrase is wrong.
+		if _, err := kr.List(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusUnauthorized, 1005, err.Error())
+			return
+		}
+
+		ttl := time.Duration(ctx.Config().Keyring.UnlockTTL) * time.Second
+		ctx.Unlock(kr, body.Passphrase, ttl)
+
+		utils.WriteResultToResponse(w, http.StatusOK, ResponseUnlockKeyring{ExpiresAt: time.Now().Add(ttl)})
+	}
+}
+
+func HandlerSignArbitrary(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
+		)
+
+		body, err := NewRequestSignArbitrary(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		info, err := ctx.Client().Keyring().Key(vars["name"])
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1003, err.Error())
+			return
+		}
+
+		signBytes, err := arbitrarySignBytes(info.GetAddress().String(), []byte(body.Message))
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			return
+		}
+
+		kr, err := unlockedKeyring(ctx, body.KeyringPassphrase)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusUnauthorized, 1005, err.Error())
+			return
+		}
+
+		signature, pubKey, err := kr.Sign(vars["name"], signBytes)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1006, err.Error())
+			return
+		}
+
+		pubKeyJSON, err := legacy.Cdc.MarshalJSON(pubKey)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1007, err.Error())
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, ResponseSignArbitrary{PubKey: pubKeyJSON, Signature: signature})
+	}
+}
+
 func HandlerDeleteKey(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
 			vars = mux.Vars(r)
 		)
 
+		if _, err := ctx.Client().Keyring().Key(vars["name"]); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1001, err.Error())
+			return
+		}
+
 		if err := ctx.Client().Keyring().Delete(vars["name"]); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
 			return
 		}
 
@@ -0,0 +1,30 @@
+package keys
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+// unlockedKeyring returns a keyring that can read private keys. The file
+// backend asks for its passphrase on every open, so a new keyring is opened
+// that reads the given or cached passphrase instead of the server's stdin.
+func unlockedKeyring(ctx *context.Context, passphrase string) (keyring.Keyring, error) {
+	if ctx.Config().Keyring.Backend != keyring.BackendFile {
+		return ctx.Client().Keyring(), nil
+	}
+	if passphrase == "" {
+		passphrase = ctx.Passphrase()
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("keyring passphrase is required for the file backend")
+	}
+
+	// The passphrase is given twice in case the keyring is new and asks for
+	// it to be re-entered.
+	return keyring.New("sentinel", keyring.BackendFile, ctx.Home(),
+		strings.NewReader(passphrase+"\n"+passphrase+"\n"))
+}
@@ -4,8 +4,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/go-bip39"
 )
 
+func validateMnemonic(v string) error {
+	switch len(strings.Fields(v)) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return fmt.Errorf("invalid field mnemonic; expected 12, 15, 18, 21 or 24 words")
+	}
+	if !bip39.IsMnemonicValid(v) {
+		return fmt.Errorf("invalid field mnemonic; checksum mismatch")
+	}
+
+	return nil
+}
+
 type RequestAddKey struct {
 	Name          string `json:"name"`
 	Mnemonic      string `json:"mnemonic"`
@@ -25,6 +42,111 @@ func (r *RequestAddKey) Validate() error {
 	if r.Name == "" {
 		return fmt.Errorf("invalid field name")
 	}
+	if r.Mnemonic != "" {
+		if err := validateMnemonic(r.Mnemonic); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type RequestImportKey struct {
+	Name          string `json:"name"`
+	Mnemonic      string `json:"mnemonic"`
+	BIP39Password string `json:"bip_39_password"`
+	HDPath        string `json:"hd_path"`
+	Account       uint32 `json:"account"`
+	Index         uint32 `json:"index"`
+}
+
+func NewRequestImportKey(r *http.Request) (*RequestImportKey, error) {
+	var body RequestImportKey
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	body.Mnemonic = strings.Join(strings.Fields(body.Mnemonic), " ")
+	return &body, nil
+}
+
+func (r *RequestImportKey) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("invalid field name")
+	}
+	if err := validateMnemonic(r.Mnemonic); err != nil {
+		return err
+	}
+	if r.HDPath != "" {
+		if _, err := hd.NewParamsFromPath(r.HDPath); err != nil {
+			return fmt.Errorf("invalid field hd_path; %s", err)
+		}
+	}
+
+	return nil
+}
+
+type RequestExportKey struct {
+	Passphrase        string `json:"passphrase"`
+	KeyringPassphrase string `json:"keyring_passphrase"`
+}
+
+func NewRequestExportKey(r *http.Request) (*RequestExportKey, error) {
+	var body RequestExportKey
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestExportKey) Validate() error {
+	if r.Passphrase == "" {
+		return fmt.Errorf("invalid field passphrase")
+	}
+
+	return nil
+}
+
+type RequestUnlockKeyring struct {
+	Passphrase string `json:"passphrase"`
+}
+
+func NewRequestUnlockKeyring(r *http.Request) (*RequestUnlockKeyring, error) {
+	var body RequestUnlockKeyring
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestUnlockKeyring) Validate() error {
+	if r.Passphrase == "" {
+		return fmt.Errorf("invalid field passphrase")
+	}
+
+	return nil
+}
+
+type RequestSignArbitrary struct {
+	Message           string `json:"message"`
+	KeyringPassphrase string `json:"keyring_passphrase"`
+}
+
+func NewRequestSignArbitrary(r *http.Request) (*RequestSignArbitrary, error) {
+	var body RequestSignArbitrary
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestSignArbitrary) Validate() error {
+	if r.Message == "" {
+		return fmt.Errorf("invalid field Message")
+	}
 
 	return nil
 }
@@ -0,0 +1,26 @@
+package keys
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/x/common"
+)
+
+type ResponseAddKey struct {
+	common.Key
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+type ResponseExportKey struct {
+	Armor string `json:"armor"`
+}
+
+type ResponseUnlockKeyring struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ResponseSignArbitrary struct {
+	PubKey    json.RawMessage `json:"pub_key"`
+	Signature []byte          `json:"signature"`
+}
@@ -19,7 +19,19 @@ func RegisterRoutes(r *mux.Router, ctx *context.Context) {
 	r.Name("AddKey").
 		Methods(http.MethodPost).Path("/keys").
 		HandlerFunc(HandlerAddKey(ctx))
+	r.Name("UnlockKeyring").
+		Methods(http.MethodPost).Path("/keys/unlock").
+		HandlerFunc(HandlerUnlockKeyring(ctx))
+	r.Name("ImportKey").
+		Methods(http.MethodPost).Path("/keys/import").
+		HandlerFunc(HandlerImportKey(ctx))
+	r.Name("ExportKey").
+		Methods(http.MethodPost).Path("/keys/{name}/export").
+		HandlerFunc(HandlerExportKey(ctx))
+	r.Name("SignArbitrary").
+		Methods(http.MethodPost).Path("/keys/{name}/sign").
+		HandlerFunc(HandlerSignArbitrary(ctx))
 	r.Name("DeleteKey").
-		Methods(http.MethodPost).Path("/keys/{name}/delete").
+		Methods(http.MethodDelete).Path("/keys/{name}").
 		HandlerFunc(HandlerDeleteKey(ctx))
 }
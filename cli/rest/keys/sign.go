@@ -0,0 +1,62 @@
+package keys
+
+import (
+	"encoding/json"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type signData struct {
+	Data   []byte `json:"data"`
+	Signer string `json:"signer"`
+}
+
+type signMsg struct {
+	Type  string   `json:"type"`
+	Value signData `json:"value"`
+}
+
+type signFee struct {
+	Amount []sdk.Coin `json:"amount"`
+	Gas    string     `json:"gas"`
+}
+
+type signDoc struct {
+	AccountNumber string    `json:"account_number"`
+	ChainID       string    `json:"chain_id"`
+	Fee           signFee   `json:"fee"`
+	Memo          string    `json:"memo"`
+	Msgs          []signMsg `json:"msgs"`
+	Sequence      string    `json:"sequence"`
+}
+
+// arbitrarySignBytes returns the ADR-036 sign bytes of the data, which is the
+// sorted amino JSON of a sign doc holding a single MsgSignData and no chain
+// ID, account number, sequence or fee, so that it can never be a valid
+// transaction.
+func arbitrarySignBytes(signer string, data []byte) ([]byte, error) {
+	doc := signDoc{
+		AccountNumber: "0",
+		Fee: signFee{
+			Amount: []sdk.Coin{},
+			Gas:    "0",
+		},
+		Msgs: []signMsg{
+			{
+				Type: "sign/MsgSignData",
+				Value: signData{
+					Data:   data,
+					Signer: signer,
+				},
+			},
+		},
+		Sequence: "0",
+	}
+
+	buf, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdk.SortJSON(buf)
+}
@@ -0,0 +1,71 @@
+package node
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultEndpointTimeout = 3
+	maxEndpointTimeout     = 30
+	endpointProbeCount     = 3
+)
+
+// wsaeConnReset is what Windows reports on a UDP socket once a datagram has
+// been answered with ICMP port unreachable.
+const wsaeConnReset = syscall.Errno(10054)
+
+func isRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, wsaeConnReset)
+}
+
+// probeUDP tells whether the UDP endpoint is reachable. As UDP has no
+// handshake, and WireGuard drops the datagrams it can't authenticate without
+// replying, this is a heuristic: a few one byte datagrams are sent from a
+// connected socket, and the endpoint counts as unreachable only when the host
+// answers with ICMP port unreachable, which the socket reports as a refused
+// read or write. Silence until the timeout counts as reachable. A reply counts
+// as reachable too, and gives the RTT.
+func probeUDP(addr *net.UDPAddr, timeout time.Duration) (reachable, replied bool, rtt time.Duration, err error) {
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return false, false, 0, err
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	buffer := make([]byte, 1500)
+	for i := 0; i < endpointProbeCount; i++ {
+		if err := conn.SetDeadline(time.Now().Add(timeout / endpointProbeCount)); err != nil {
+			return false, false, 0, err
+		}
+
+		start := time.Now()
+		if _, err := conn.Write([]byte{0}); err != nil {
+			if isRefused(err) {
+				return false, false, 0, nil
+			}
+
+			return false, false, 0, err
+		}
+
+		if _, err := conn.Read(buffer); err != nil {
+			if isRefused(err) {
+				return false, false, 0, nil
+			}
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+
+			return false, false, 0, err
+		}
+
+		return true, true, time.Since(start), nil
+	}
+
+	return true, false, 0, nil
+}
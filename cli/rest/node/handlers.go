@@ -1,8 +1,12 @@
 package node
 
 import (
+	gocontext "context"
+	"fmt"
+	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	hubtypes "github.com/sentinel-official/hub/types"
@@ -15,7 +19,8 @@ import (
 func HandlerGetNode(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			vars = mux.Vars(r)
+			values = r.URL.Query()
+			vars   = mux.Vars(r)
 		)
 
 		address, err := hubtypes.NodeAddressFromBech32(vars["address"])
@@ -29,8 +34,23 @@ func HandlerGetNode(ctx *context.Context) http.HandlerFunc {
 			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
 			return
 		}
+		if res == nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1003, "node does not exist")
+			return
+		}
+
+		item := ResponseNode{
+			Node: node.NewNodeFromRaw(res),
+		}
+
+		if values.Get("details") == "true" {
+			item.Info, err = queryNodeInfo(r.Context(), res.RemoteURL)
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusBadGateway, 1004, err.Error())
+				return
+			}
+		}
 
-		item := node.NewNodeFromRaw(res)
 		utils.WriteResultToResponse(w, http.StatusOK, item)
 	}
 }
@@ -38,24 +58,50 @@ func HandlerGetNode(ctx *context.Context) http.HandlerFunc {
 func HandlerGetNodes(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			values = r.URL.Query()
-			status = hubtypes.StatusFromString(values.Get("status"))
+			values  = r.URL.Query()
+			country = values.Get("country")
 		)
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
 			return
 		}
 
-		res, err := ctx.Client().QueryNodes(status, pagination)
+		status, err := utils.ParseStatusQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
 			return
 		}
 
-		items := node.NewNodesFromRaw(res)
-		utils.WriteResultToResponse(w, http.StatusOK, items)
+		if country == "" {
+			res, err := ctx.Client().QueryNodes(status, pagination)
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+				return
+			}
+
+			utils.WriteResultToResponse(w, http.StatusOK, node.NewNodesFromRaw(res))
+			return
+		}
+		if pagination.Key != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, "invalid query key; nodes filtered by country are paged by offset")
+			return
+		}
+
+		// The chain knows nothing of the country of a node, so all of them are
+		// filtered before the page is cut.
+		res, err := ctx.Client().QueryAllNodes(status)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			return
+		}
+
+		c, cancel := gocontext.WithTimeout(r.Context(), maxFilterDuration)
+		defer cancel()
+
+		items := filterNodesByCountry(c, node.NewNodesFromRaw(res), country)
+		utils.WriteResultToResponse(w, http.StatusOK, items.Page(pagination.Offset, pagination.Limit))
 	}
 }
 
@@ -68,7 +114,7 @@ func HandlerGetNodesForPlan(ctx *context.Context) http.HandlerFunc {
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
 			return
 		}
 
@@ -88,3 +134,194 @@ func HandlerGetNodesForPlan(ctx *context.Context) http.HandlerFunc {
 		utils.WriteResultToResponse(w, http.StatusOK, items)
 	}
 }
+
+func HandlerPingNode(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			values = r.URL.Query()
+			vars   = mux.Vars(r)
+			count  = defaultPingCount
+		)
+
+		address, err := hubtypes.NodeAddressFromBech32(vars["address"])
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+
+		if values.Get("count") != "" {
+			count, err = strconv.Atoi(values.Get("count"))
+			if err != nil || count <= 0 || count > maxPingCount {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, "invalid query count")
+				return
+			}
+		}
+
+		res, err := ctx.Client().QueryNode(address)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			return
+		}
+		if res == nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1004, "node does not exist")
+			return
+		}
+
+		addr, err := resolveEndpoint(r.Context(), ctx.Resolver(), res.RemoteURL)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadGateway, 1005, fmt.Sprintf("failed to resolve node endpoint: %s", err))
+			return
+		}
+
+		item, err := ping(addr, count)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadGateway, 1006, err.Error())
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerTestNodeEndpoint(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := NewRequestTestNodeEndpoint(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		remoteURL := body.RemoteURL
+		if body.Address != "" {
+			address, _ := hubtypes.NodeAddressFromBech32(body.Address)
+
+			res, err := ctx.Client().QueryNode(address)
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+				return
+			}
+			if res == nil {
+				utils.WriteErrorToResponse(w, http.StatusNotFound, 1004, "node does not exist")
+				return
+			}
+
+			remoteURL = res.RemoteURL
+		}
+
+		addr, err := resolveEndpoint(r.Context(), ctx.Resolver(), remoteURL)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadGateway, 1005, fmt.Sprintf("failed to resolve node endpoint: %s", err))
+			return
+		}
+
+		timeout := uint64(defaultEndpointTimeout)
+		if body.Timeout != 0 {
+			timeout = body.Timeout
+		}
+
+		udpAddr := &net.UDPAddr{IP: addr.IP, Port: int(body.Port), Zone: addr.Zone}
+		reachable, replied, rtt, err := probeUDP(udpAddr, time.Duration(timeout)*time.Second)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadGateway, 1006, err.Error())
+			return
+		}
+
+		item := ResponseTestNodeEndpoint{
+			Address:   udpAddr.String(),
+			Reachable: reachable,
+			Replied:   replied,
+		}
+
+		// Without a reply the UDP probe gives no RTT, so the host is pinged
+		// instead.
+		switch {
+		case replied:
+			item.Method, item.RTT = "udp", milliseconds(rtt)
+		case reachable:
+			if res, err := ping(addr, 1); err == nil {
+				item.Method, item.RTT = res.Method, res.Avg
+			}
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerRankNodes(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := NewRequestRankNodes(r)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			return
+		}
+
+		var (
+			denom         = defaultRankDenom
+			timeout       = uint64(defaultRankTimeout)
+			latencyWeight = defaultLatencyWeight
+			priceWeight   = defaultPriceWeight
+		)
+
+		if body.Denom != "" {
+			denom = body.Denom
+		}
+		if body.Timeout != 0 {
+			timeout = body.Timeout
+		}
+		if body.LatencyWeight != nil {
+			latencyWeight = *body.LatencyWeight
+		}
+		if body.PriceWeight != nil {
+			priceWeight = *body.PriceWeight
+		}
+
+		// A node that cannot be queried is reported with its error, so that
+		// the others are still ranked.
+		var (
+			items  = make(node.Nodes, 0, len(body.Addresses))
+			failed []ResponseRankedNode
+		)
+
+		if len(body.Addresses) > 0 {
+			for _, s := range body.Addresses {
+				address, _ := hubtypes.NodeAddressFromBech32(s)
+
+				res, err := ctx.Client().QueryNode(address)
+				if err != nil {
+					failed = append(failed, ResponseRankedNode{Address: s, Error: fmt.Sprintf("failed to query node: %s", err)})
+					continue
+				}
+				if res == nil {
+					failed = append(failed, ResponseRankedNode{Address: s, Error: "node does not exist"})
+					continue
+				}
+
+				items = append(items, node.NewNodeFromRaw(res))
+			}
+		} else {
+			res, err := ctx.Client().QueryAllNodes(hubtypes.StatusActive)
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
+				return
+			}
+
+			items = node.NewNodesFromRaw(res)
+		}
+
+		c, cancel := gocontext.WithTimeout(r.Context(), maxRankDuration)
+		defer cancel()
+
+		ranked := append(rankNodes(c, ctx.Resolver(), items, denom, body.Country, time.Duration(timeout)*time.Second), failed...)
+		scoreNodes(ranked, latencyWeight, priceWeight)
+
+		utils.WriteResultToResponse(w, http.StatusOK, ranked)
+	}
+}
@@ -0,0 +1,114 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/utils"
+	"github.com/sentinel-official/desktop-client/cli/x/node"
+)
+
+const (
+	infoConcurrency   = 8
+	infoTimeout       = 5 * time.Second
+	maxFilterDuration = 30 * time.Second
+)
+
+// filterNodesByCountry returns the nodes of the country, querying the info of
+// no more than infoConcurrency of them at once until the context is done. A
+// node whose info could not be queried by then is left out, as its country is
+// unknown.
+func filterNodesByCountry(c context.Context, items node.Nodes, country string) node.Nodes {
+	var (
+		wg       sync.WaitGroup
+		matches  = make([]bool, len(items))
+		indexes  = make(chan int)
+		filtered = make(node.Nodes, 0, len(items))
+	)
+
+	for n := 0; n < infoConcurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				info, err := queryNodeInfo(c, items[i].RemoteURL)
+				if err != nil {
+					continue
+				}
+
+				matches[i] = strings.EqualFold(info.Location.Country, country)
+			}
+		}()
+	}
+
+loop:
+	for i := range items {
+		select {
+		case indexes <- i:
+		case <-c.Done():
+			break loop
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	for i := range items {
+		if matches[i] {
+			filtered = append(filtered, items[i])
+		}
+	}
+
+	return filtered
+}
+
+// queryNodeInfo queries the status of the node, pinning its certificate to the
+// fingerprint from the node record when there is one.
+func queryNodeInfo(c context.Context, remoteURL string) (*node.Info, error) {
+	remoteURL, fingerprint, err := utils.SplitNodeRemoteURL(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodGet, strings.TrimSuffix(remoteURL, "/")+"/status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Timeout: infoTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig:   utils.NodeTLSConfig(fingerprint),
+			DisableKeepAlives: true,
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var res struct {
+		Success bool      `json:"success"`
+		Result  node.Info `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if !res.Success {
+		return nil, fmt.Errorf("node returned an unsuccessful status response")
+	}
+
+	return &res.Result, nil
+}
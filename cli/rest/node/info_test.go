@@ -0,0 +1,60 @@
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/x/node"
+)
+
+// newStatusServer returns a TLS server answering the status of a node of the
+// country, and the remote URL that pins its certificate.
+func newStatusServer(t *testing.T, country string) (*httptest.Server, string) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":true,"result":{"location":{"country":"` + country + `"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	return server, server.URL + "#sha256=" + hex.EncodeToString(sum[:])
+}
+
+func TestFilterNodesByCountry(t *testing.T) {
+	de, pinnedDE := newStatusServer(t, "DE")
+	_, pinnedUS := newStatusServer(t, "US")
+
+	items := node.Nodes{
+		{Address: "pinned-de", RemoteURL: pinnedDE},
+		{Address: "pinned-us", RemoteURL: pinnedUS},
+		{Address: "unpinned-de", RemoteURL: de.URL},
+		{Address: "wrong-pin-de", RemoteURL: de.URL + "#sha256=" + strings.Repeat("ab", 32)},
+	}
+
+	filtered := filterNodesByCountry(context.Background(), items, "de")
+	if len(filtered) != 1 || filtered[0].Address != "pinned-de" {
+		t.Fatalf("expected only the pinned node of the country, got %v", filtered)
+	}
+}
+
+func TestFilterNodesByCountryCancelled(t *testing.T) {
+	_, pinned := newStatusServer(t, "DE")
+
+	items := make(node.Nodes, 0, 2*infoConcurrency)
+	for i := 0; i < 2*infoConcurrency; i++ {
+		items = append(items, node.Node{Address: string(rune('a' + i)), RemoteURL: pinned})
+	}
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if filtered := filterNodesByCountry(c, items, "DE"); len(filtered) != 0 {
+		t.Fatalf("expected no nodes once the context is done, got %d", len(filtered))
+	}
+}
@@ -0,0 +1,169 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	neturl "net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	defaultPingCount = 4
+	maxPingCount     = 20
+	pingTimeout      = 2 * time.Second
+)
+
+func resolveEndpoint(c context.Context, resolver *net.Resolver, remoteURL string) (*net.TCPAddr, error) {
+	u, err := neturl.Parse(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid node remote URL %s", remoteURL)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	n, err := net.LookupPort("tcp", port)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := resolver.LookupIPAddr(c, u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.TCPAddr{IP: addrs[0].IP, Port: n, Zone: addrs[0].Zone}, nil
+}
+
+func pingICMP(ip net.IP, seq int) (time.Duration, error) {
+	if ip.To4() == nil {
+		return 0, fmt.Errorf("ICMP probing supports only IPv4")
+	}
+
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	message := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{
+			ID:   os.Getpid() & 0xffff,
+			Seq:  seq,
+			Data: []byte("sentinel"),
+		},
+	}
+
+	data, err := message.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+	if err := conn.SetDeadline(time.Now().Add(pingTimeout)); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := conn.WriteTo(data, &net.UDPAddr{IP: ip}); err != nil {
+		return 0, err
+	}
+
+	buffer := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return 0, err
+		}
+
+		reply, err := icmp.ParseMessage(1, buffer[:n])
+		if err != nil {
+			return 0, err
+		}
+		if reply.Type == ipv4.ICMPTypeEchoReply {
+			return time.Since(start), nil
+		}
+	}
+}
+
+func pingTCP(addr *net.TCPAddr) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr.String(), pingTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	rtt := time.Since(start)
+	_ = conn.Close()
+
+	return rtt, nil
+}
+
+func ping(addr *net.TCPAddr, count int) (*ResponsePing, error) {
+	var (
+		method = "icmp"
+		rtts   = make([]time.Duration, 0, count)
+	)
+
+	for i := 0; i < count; i++ {
+		rtt, err := pingICMP(addr.IP, i)
+		if err != nil {
+			method = "tcp"
+			break
+		}
+
+		rtts = append(rtts, rtt)
+	}
+
+	if method == "tcp" {
+		rtts = rtts[:0]
+		for i := 0; i < count; i++ {
+			rtt, err := pingTCP(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			rtts = append(rtts, rtt)
+		}
+	}
+
+	var minRTT, maxRTT, total time.Duration
+	for i, rtt := range rtts {
+		if i == 0 || rtt < minRTT {
+			minRTT = rtt
+		}
+		if rtt > maxRTT {
+			maxRTT = rtt
+		}
+
+		total += rtt
+	}
+
+	return &ResponsePing{
+		Address: addr.String(),
+		Method:  method,
+		Count:   len(rtts),
+		Min:     milliseconds(minRTT),
+		Avg:     milliseconds(total / time.Duration(len(rtts))),
+		Max:     milliseconds(maxRTT),
+	}, nil
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1e3
+}
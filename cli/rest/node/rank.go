@@ -0,0 +1,204 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/x/node"
+)
+
+const (
+	defaultRankDenom     = "udvpn"
+	defaultRankTimeout   = 5
+	maxRankTimeout       = 30
+	maxRankCandidates    = 200
+	maxRankDuration      = 60 * time.Second
+	rankConcurrency      = 8
+	rankPingCount        = 3
+	defaultLatencyWeight = 0.5
+	defaultPriceWeight   = 0.5
+)
+
+// rankNode measures the latency of the node and reads its price in the denom.
+// When a country is given, a node of another country is left out, and so is
+// one whose country is unknown as its info could not be queried.
+func rankNode(resolver *net.Resolver, item node.Node, denom, country string) (ranked ResponseRankedNode, ok bool) {
+	ranked = ResponseRankedNode{
+		Address: item.Address,
+	}
+
+	for _, coin := range item.Price {
+		if coin.Denom == denom {
+			ranked.Price = coin.Value
+		}
+	}
+
+	info, err := queryNodeInfo(context.Background(), item.RemoteURL)
+	if err != nil {
+		ranked.Error = fmt.Sprintf("failed to query node info: %s", err)
+		return ranked, country == ""
+	}
+	if country != "" && !strings.EqualFold(info.Location.Country, country) {
+		return ranked, false
+	}
+
+	ranked.Moniker, ranked.Country, ranked.Bandwidth = info.Moniker, info.Location.Country, &info.Bandwidth
+
+	c, cancel := context.WithTimeout(context.Background(), infoTimeout)
+	defer cancel()
+
+	addr, err := resolveEndpoint(c, resolver, item.RemoteURL)
+	if err != nil {
+		ranked.Error = fmt.Sprintf("failed to resolve node endpoint: %s", err)
+		return ranked, true
+	}
+
+	res, err := ping(addr, rankPingCount)
+	if err != nil {
+		ranked.Error = err.Error()
+		return ranked, true
+	}
+
+	ranked.Latency = res.Avg
+	if ranked.Price == 0 {
+		ranked.Error = fmt.Sprintf("node has no price in %s", denom)
+	}
+
+	return ranked, true
+}
+
+// rankNodes measures the nodes concurrently, giving each one the timeout and
+// all of them until the context is done. A node that exceeds either is
+// reported as timed out while its probe finishes in the background, still
+// holding its slot so that no more than rankConcurrency probes run at once.
+// With a country given, such a node is left out, as its country is unknown.
+func rankNodes(c context.Context, resolver *net.Resolver, items node.Nodes, denom, country string, timeout time.Duration) []ResponseRankedNode {
+	var (
+		wg      sync.WaitGroup
+		results = make([]ResponseRankedNode, len(items))
+		keep    = make([]bool, len(items))
+		sem     = make(chan struct{}, rankConcurrency)
+	)
+
+	timedOut := func(i int, err string) {
+		results[i], keep[i] = ResponseRankedNode{
+			Address: items[i].Address,
+			Error:   err,
+		}, country == ""
+	}
+
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-c.Done():
+				timedOut(i, "ranking deadline exceeded")
+				return
+			}
+
+			type result struct {
+				ranked ResponseRankedNode
+				ok     bool
+			}
+
+			done := make(chan result, 1)
+			go func() {
+				defer func() { <-sem }()
+
+				ranked, ok := rankNode(resolver, items[i], denom, country)
+				done <- result{ranked, ok}
+			}()
+
+			select {
+			case res := <-done:
+				results[i], keep[i] = res.ranked, res.ok
+			case <-time.After(timeout):
+				timedOut(i, fmt.Sprintf("timed out after %s", timeout))
+			case <-c.Done():
+				timedOut(i, "ranking deadline exceeded")
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	ranked := make([]ResponseRankedNode, 0, len(items))
+	for i := range results {
+		if keep[i] {
+			ranked = append(ranked, results[i])
+		}
+	}
+
+	return ranked
+}
+
+// scoreNodes scores the nodes measured without an error by the weighted sum
+// of their latency and price, each scaled to 0-1 between the lowest and the
+// highest of them, and sorts them by score, lowest first. The nodes with an
+// error follow, sorted by address.
+func scoreNodes(items []ResponseRankedNode, latencyWeight, priceWeight float64) {
+	var (
+		first                  = true
+		minLatency, maxLatency float64
+		minPrice, maxPrice     float64
+	)
+
+	for _, item := range items {
+		if item.Error != "" {
+			continue
+		}
+		if first || item.Latency < minLatency {
+			minLatency = item.Latency
+		}
+		if first || item.Latency > maxLatency {
+			maxLatency = item.Latency
+		}
+		if first || float64(item.Price) < minPrice {
+			minPrice = float64(item.Price)
+		}
+		if first || float64(item.Price) > maxPrice {
+			maxPrice = float64(item.Price)
+		}
+
+		first = false
+	}
+
+	scale := func(v, min, max float64) float64 {
+		if max == min {
+			return 0
+		}
+
+		return (v - min) / (max - min)
+	}
+
+	for i := range items {
+		if items[i].Error != "" {
+			continue
+		}
+
+		items[i].Score = latencyWeight*scale(items[i].Latency, minLatency, maxLatency) +
+			priceWeight*scale(float64(items[i].Price), minPrice, maxPrice)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if (items[i].Error == "") != (items[j].Error == "") {
+			return items[i].Error == ""
+		}
+		if items[i].Error != "" {
+			return items[i].Address < items[j].Address
+		}
+		if items[i].Score != items[j].Score {
+			return items[i].Score < items[j].Score
+		}
+
+		return items[i].Latency < items[j].Latency
+	})
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/x/node"
+)
+
+// unreachableNodes returns nodes whose info query is refused right away.
+func unreachableNodes(t *testing.T, n int) node.Nodes {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	items := make(node.Nodes, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, node.Node{Address: string(rune('a' + i)), RemoteURL: "https://" + addr})
+	}
+
+	return items
+}
+
+func TestRankNodesInfoFailure(t *testing.T) {
+	items := unreachableNodes(t, 3)
+
+	ranked := rankNodes(context.Background(), net.DefaultResolver, items, defaultRankDenom, "", 5*time.Second)
+	if len(ranked) != len(items) {
+		t.Fatalf("expected %d nodes without a country, got %d", len(items), len(ranked))
+	}
+	for _, item := range ranked {
+		if item.Error == "" {
+			t.Errorf("expected an error for node %s", item.Address)
+		}
+	}
+
+	ranked = rankNodes(context.Background(), net.DefaultResolver, items, defaultRankDenom, "DE", 5*time.Second)
+	if len(ranked) != 0 {
+		t.Fatalf("expected nodes of unknown country left out, got %d", len(ranked))
+	}
+}
+
+func TestRankNodesDeadline(t *testing.T) {
+	items := unreachableNodes(t, 2*rankConcurrency)
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ranked := rankNodes(c, net.DefaultResolver, items, defaultRankDenom, "", 5*time.Second)
+	if len(ranked) != len(items) {
+		t.Fatalf("expected %d nodes, got %d", len(items), len(ranked))
+	}
+	for _, item := range ranked {
+		if item.Error == "" {
+			t.Errorf("expected an error for node %s", item.Address)
+		}
+	}
+}
+
+func TestScoreNodes(t *testing.T) {
+	items := []ResponseRankedNode{
+		{Address: "slow-cheap", Latency: 100, Price: 10},
+		{Address: "failed-b", Error: "timed out"},
+		{Address: "fast-dear", Latency: 10, Price: 100},
+		{Address: "fast-cheap", Latency: 10, Price: 10},
+		{Address: "failed-a", Error: "node does not exist"},
+	}
+
+	scoreNodes(items, 0.5, 0.5)
+
+	// slow-cheap and fast-dear tie on score, so the lower latency goes first.
+	expected := []string{"fast-cheap", "fast-dear", "slow-cheap", "failed-a", "failed-b"}
+	for i := range expected {
+		if items[i].Address != expected[i] {
+			t.Fatalf("expected %s at %d, got %s", expected[i], i, items[i].Address)
+		}
+	}
+	if items[0].Score != 0 {
+		t.Errorf("expected score 0 for the best node, got %f", items[0].Score)
+	}
+}
@@ -1 +1,97 @@
 package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	neturl "net/url"
+
+	hubtypes "github.com/sentinel-official/hub/types"
+)
+
+type RequestTestNodeEndpoint struct {
+	Address   string `json:"address"`
+	RemoteURL string `json:"remote_url"`
+	Port      uint64 `json:"port"`
+	Timeout   uint64 `json:"timeout"`
+}
+
+func NewRequestTestNodeEndpoint(r *http.Request) (*RequestTestNodeEndpoint, error) {
+	var body RequestTestNodeEndpoint
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestTestNodeEndpoint) Validate() error {
+	if (r.Address == "") == (r.RemoteURL == "") {
+		return fmt.Errorf("invalid fields Address and RemoteURL; expected only one of them")
+	}
+	if r.Address != "" {
+		if _, err := hubtypes.NodeAddressFromBech32(r.Address); err != nil {
+			return fmt.Errorf("invalid field Address")
+		}
+	}
+	if r.RemoteURL != "" {
+		if _, err := neturl.ParseRequestURI(r.RemoteURL); err != nil {
+			return fmt.Errorf("invalid field RemoteURL")
+		}
+	}
+	if r.Port < 1 || r.Port > math.MaxUint16 {
+		return fmt.Errorf("invalid field Port; expected value in range 1-65535")
+	}
+	if r.Timeout > maxEndpointTimeout {
+		return fmt.Errorf("invalid field Timeout")
+	}
+
+	return nil
+}
+
+type RequestRankNodes struct {
+	Addresses     []string `json:"addresses"`
+	Country       string   `json:"country"`
+	Denom         string   `json:"denom"`
+	LatencyWeight *float64 `json:"latency_weight"`
+	PriceWeight   *float64 `json:"price_weight"`
+	Timeout       uint64   `json:"timeout"`
+}
+
+func NewRequestRankNodes(r *http.Request) (*RequestRankNodes, error) {
+	var body RequestRankNodes
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestRankNodes) Validate() error {
+	if len(r.Addresses) == 0 && r.Country == "" {
+		return fmt.Errorf("invalid fields Addresses and Country; expected at least one of them")
+	}
+	if len(r.Addresses) > maxRankCandidates {
+		return fmt.Errorf("invalid field Addresses; expected at most %d values", maxRankCandidates)
+	}
+	for _, address := range r.Addresses {
+		if _, err := hubtypes.NodeAddressFromBech32(address); err != nil {
+			return fmt.Errorf("invalid field Addresses")
+		}
+	}
+	if r.LatencyWeight != nil && (*r.LatencyWeight < 0 || math.IsNaN(*r.LatencyWeight) || math.IsInf(*r.LatencyWeight, 0)) {
+		return fmt.Errorf("invalid field LatencyWeight; expected non-negative value")
+	}
+	if r.PriceWeight != nil && (*r.PriceWeight < 0 || math.IsNaN(*r.PriceWeight) || math.IsInf(*r.PriceWeight, 0)) {
+		return fmt.Errorf("invalid field PriceWeight; expected non-negative value")
+	}
+	if r.LatencyWeight != nil && r.PriceWeight != nil && *r.LatencyWeight == 0 && *r.PriceWeight == 0 {
+		return fmt.Errorf("invalid fields LatencyWeight and PriceWeight; expected at least one positive value")
+	}
+	if r.Timeout > maxRankTimeout {
+		return fmt.Errorf("invalid field Timeout")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package node
+
+import (
+	"github.com/sentinel-official/desktop-client/cli/x/common"
+	"github.com/sentinel-official/desktop-client/cli/x/node"
+)
+
+type ResponsePing struct {
+	Address string  `json:"address"`
+	Method  string  `json:"method"`
+	Count   int     `json:"count"`
+	Min     float64 `json:"min_ms"`
+	Avg     float64 `json:"avg_ms"`
+	Max     float64 `json:"max_ms"`
+}
+
+type ResponseTestNodeEndpoint struct {
+	Address   string  `json:"address"`
+	Reachable bool    `json:"reachable"`
+	Replied   bool    `json:"replied"`
+	Method    string  `json:"method,omitempty"`
+	RTT       float64 `json:"rtt_ms,omitempty"`
+}
+
+type ResponseNode struct {
+	node.Node
+	Info *node.Info `json:"info,omitempty"`
+}
+
+type ResponseRankedNode struct {
+	Address   string            `json:"address"`
+	Moniker   string            `json:"moniker,omitempty"`
+	Country   string            `json:"country,omitempty"`
+	Bandwidth *common.Bandwidth `json:"bandwidth,omitempty"`
+	Price     int64             `json:"price,omitempty"`
+	Latency   float64           `json:"latency_ms,omitempty"`
+	Score     float64           `json:"score"`
+	Error     string            `json:"error,omitempty"`
+}
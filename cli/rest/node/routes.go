@@ -10,12 +10,21 @@ import (
 
 func RegisterRoutes(r *mux.Router, ctx *context.Context) {
 	r.Name("GetNode").
-		Methods(http.MethodGet).Path("/nodes/{address}").
+		Methods(http.MethodGet, http.MethodHead).Path("/nodes/{address}").
 		HandlerFunc(HandlerGetNode(ctx))
+	r.Name("PingNode").
+		Methods(http.MethodPost).Path("/nodes/{address}/ping").
+		HandlerFunc(HandlerPingNode(ctx))
+	r.Name("TestNodeEndpoint").
+		Methods(http.MethodPost).Path("/nodes/test-endpoint").
+		HandlerFunc(HandlerTestNodeEndpoint(ctx))
+	r.Name("RankNodes").
+		Methods(http.MethodPost).Path("/nodes/rank").
+		HandlerFunc(HandlerRankNodes(ctx))
 	r.Name("GetNodes").
-		Methods(http.MethodGet).Path("/nodes").
+		Methods(http.MethodGet, http.MethodHead).Path("/nodes").
 		HandlerFunc(HandlerGetNodes(ctx))
 	r.Name("GetNodesForPlan").
-		Methods(http.MethodGet).Path("/plans/{id}/nodes").
+		Methods(http.MethodGet, http.MethodHead).Path("/plans/{id}/nodes").
 		HandlerFunc(HandlerGetNodesForPlan(ctx))
 }
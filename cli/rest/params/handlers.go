@@ -0,0 +1,28 @@
+package params
+
+import (
+	"net/http"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+	"github.com/sentinel-official/desktop-client/cli/x/common"
+)
+
+func HandlerGetParams(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := ctx.Client().QueryParams()
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			return
+		}
+
+		var item ResponseParams
+		item.Node.Deposit = common.NewCoinFromRaw(&res.Node.Deposit)
+		item.Node.InactiveDuration = res.Node.InactiveDuration.Nanoseconds()
+		item.Subscription.InactiveDuration = res.Subscription.InactiveDuration.Nanoseconds()
+		item.Session.InactiveDuration = res.Session.InactiveDuration.Nanoseconds()
+		item.Session.ProofVerificationEnabled = res.Session.ProofVerificationEnabled
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
@@ -0,0 +1,19 @@
+package params
+
+import (
+	"github.com/sentinel-official/desktop-client/cli/x/common"
+)
+
+type ResponseParams struct {
+	Node struct {
+		Deposit          common.Coin `json:"deposit"`
+		InactiveDuration int64       `json:"inactive_duration"`
+	} `json:"node"`
+	Subscription struct {
+		InactiveDuration int64 `json:"inactive_duration"`
+	} `json:"subscription"`
+	Session struct {
+		InactiveDuration         int64 `json:"inactive_duration"`
+		ProofVerificationEnabled bool  `json:"proof_verification_enabled"`
+	} `json:"session"`
+}
@@ -0,0 +1,15 @@
+package params
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("GetParams").
+		Methods(http.MethodGet).Path("/params").
+		HandlerFunc(HandlerGetParams(ctx))
+}
@@ -42,7 +42,7 @@ func HandlerGetProviders(ctx *context.Context) http.HandlerFunc {
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
 			return
 		}
 
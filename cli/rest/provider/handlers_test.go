@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func TestHandlerGetProvidersBadPagination(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"negative limit", "limit=-1"},
+		{"non-numeric limit", "limit=ten"},
+		{"negative offset", "offset=-1"},
+		{"non-numeric offset", "offset=ten"},
+		{"invalid key", "key=xyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodGet, "/providers?"+tt.query, nil)
+			)
+
+			HandlerGetProviders(context.NewContext())(w, r)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
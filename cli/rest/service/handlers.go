@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
-	"path/filepath"
 
 	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/rest/session"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
 	"github.com/sentinel-official/desktop-client/cli/types"
 	"github.com/sentinel-official/desktop-client/cli/utils"
 	"github.com/sentinel-official/desktop-client/cli/x/common"
@@ -14,71 +15,126 @@ import (
 
 func HandlerStatus(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		service := ctx.Service()
-		if service == nil {
-			utils.WriteResultToResponse(w, http.StatusOK, nil)
-			return
-		}
+		var (
+			ids   = ctx.ServiceIDs()
+			items = make([]ResponseStatus, 0, len(ids))
+		)
 
-		info := service.Info()
-		if info == nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, "")
-			return
-		}
+		for _, id := range ids {
+			service := ctx.Service(id)
+			if service == nil {
+				continue
+			}
 
-		var status types.Status
-		if err := json.Unmarshal(info, &status); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
-			return
-		}
+			info := service.Info()
+			if info == nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, "")
+				return
+			}
 
-		upload, download, err := service.Transfer()
-		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
-			return
-		}
+			var status types.Status
+			if err := json.Unmarshal(info, &status); err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
+				return
+			}
 
-		utils.WriteResultToResponse(w, http.StatusOK,
-			ResponseStatus{
-				From: status.From,
-				ID:   status.ID,
-				To:   status.To,
-				Bandwidth: common.Bandwidth{
-					Upload:   upload,
-					Download: download,
+			upload, download, err := service.Transfer()
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+				return
+			}
+
+			items = append(items,
+				ResponseStatus{
+					From: status.From,
+					ID:   status.ID,
+					To:   status.To,
+					Bandwidth: common.Bandwidth{
+						Upload:   upload,
+						Download: download,
+					},
 				},
-			},
-		)
+			)
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, items)
 	}
 }
 
 func HandlerDisconnect(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		service := ctx.Service()
-		if service != nil {
-			if err := service.PreDown(); err != nil {
-				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
-				return
+		for _, id := range ctx.ServiceIDs() {
+			service := ctx.Service(id)
+			if service == nil {
+				continue
 			}
-			if err := service.Down(); err != nil {
-				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
-				return
-			}
-			if err := service.PostDown(); err != nil {
-				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+
+			if err := session.StopSession(ctx, id, service); err != nil {
+				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
 				return
 			}
 		}
 
-		path := filepath.Join(ctx.Home(), "status.json")
-		if _, err := os.Stat(path); err == nil {
-			if err = os.Remove(path); err != nil {
-				utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
-				return
+		utils.WriteResultToResponse(w, http.StatusOK, nil)
+	}
+}
+
+// HandlerDisconnectAll stops every session, carrying on past failures so that
+// one broken interface does not keep the others up. Sessions that fail to stop
+// keep their status file, so they are cleaned up on the next start.
+func HandlerDisconnectAll(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ids   = ctx.ServiceIDs()
+			items = make([]ResponseDisconnect, 0, len(ids))
+		)
+
+		for _, id := range ids {
+			service := ctx.Service(id)
+			if service == nil {
+				continue
 			}
+
+			item := ResponseDisconnect{
+				ID: id,
+			}
+
+			var status types.Status
+			if err := json.Unmarshal(service.Info(), &status); err == nil {
+				item.Interface = status.Name
+			}
+
+			switch err := utils.StopService(service).(type) {
+			case nil:
+			case utils.Errors:
+				for _, err := range err {
+					item.Errors = append(item.Errors, err.Error())
+				}
+			default:
+				item.Errors = append(item.Errors, err.Error())
+			}
+
+			if len(item.Errors) == 0 {
+				path := types.StatusFilePath(ctx.Home(), id)
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					item.Errors = append(item.Errors, err.Error())
+				}
+
+				ctx.WithService(id, nil)
+				ctx.NotifyWebhook(context.WebhookEventSessionStopped, id, service)
+				item.Stopped = true
+			}
+
+			items = append(items, item)
 		}
 
-		ctx = ctx.WithService(nil)
-		utils.WriteResultToResponse(w, http.StatusOK, nil)
+		result := ResponseDisconnectAll{
+			Sessions: items,
+		}
+		if err := wireguard.FlushKillSwitch(ctx.Home()); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, result)
 	}
 }
@@ -10,3 +10,15 @@ type ResponseStatus struct {
 	ID        uint64           `json:"id"`
 	To        string           `json:"to"`
 }
+
+type ResponseDisconnect struct {
+	ID        uint64   `json:"id"`
+	Interface string   `json:"interface"`
+	Stopped   bool     `json:"stopped"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+type ResponseDisconnectAll struct {
+	Sessions []ResponseDisconnect `json:"sessions"`
+	Errors   []string             `json:"errors,omitempty"`
+}
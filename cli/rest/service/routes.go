@@ -9,6 +9,9 @@ import (
 )
 
 func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("DisconnectAll").
+		Methods(http.MethodPost).Path("/disconnect").
+		HandlerFunc(HandlerDisconnectAll(ctx))
 	r.Name("ServiceDisconnect").
 		Methods(http.MethodPost).Path("/service/disconnect").
 		HandlerFunc(HandlerDisconnect(ctx))
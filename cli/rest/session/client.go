@@ -0,0 +1,233 @@
+package session
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/transport/http/jsonrpc"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/metrics"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+// checkCertificateFingerprint checks the fingerprint the request expects
+// against the one the node publishes on chain, which is the only one pinned.
+func checkCertificateFingerprint(expected string, fingerprint []byte) error {
+	if expected == "" {
+		return nil
+	}
+	if fingerprint == nil {
+		return fmt.Errorf("node publishes no certificate fingerprint on chain")
+	}
+	if v, _ := hex.DecodeString(expected); !bytes.Equal(v, fingerprint) {
+		return fmt.Errorf("certificate fingerprint does not match the one the node publishes on chain")
+	}
+
+	return nil
+}
+
+// newNodeHTTPClient returns the client for the session request to the node,
+// which goes through node->proxy_url, or the proxy the environment sets when
+// there is none. The certificate is pinned to the fingerprint from the node
+// record when there is one. The timeout is in seconds and a value of zero
+// means no timeout.
+func newNodeHTTPClient(ctx *context.Context, body *RequestAddSession, fingerprint []byte) *http.Client {
+	config := utils.NodeTLSConfig(fingerprint)
+	if fingerprint == nil && body.Insecure {
+		config.InsecureSkipVerify = true
+	}
+
+	timeout := ctx.Config().Node.Timeout
+	if body.Timeout != nil {
+		timeout = *body.Timeout
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if v := ctx.Config().Node.ProxyURL; v != "" {
+		u, _ := neturl.Parse(v)
+		proxy = http.ProxyURL(u)
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           proxy,
+			TLSClientConfig: config,
+		},
+		Timeout: time.Duration(timeout) * time.Second,
+	}
+}
+
+// resolveNodeAPI returns the address and port of the node API endpoint.
+func resolveNodeAPI(c gocontext.Context, resolver *net.Resolver, endpoint string) (wgt.Endpoint, error) {
+	u, err := neturl.Parse(endpoint)
+	if err != nil {
+		return wgt.Endpoint{}, err
+	}
+
+	port := uint64(443)
+	if v := u.Port(); v != "" {
+		port, err = strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return wgt.Endpoint{}, err
+		}
+	}
+
+	ip := net.ParseIP(u.Hostname())
+	if ip == nil {
+		addrs, err := resolver.LookupIPAddr(c, u.Hostname())
+		if err != nil {
+			return wgt.Endpoint{}, err
+		}
+		if len(addrs) == 0 {
+			return wgt.Endpoint{}, fmt.Errorf("no address found for node host %s", u.Hostname())
+		}
+
+		ip = addrs[0].IP
+	}
+
+	return wgt.Endpoint{Host: ip.String(), Port: uint16(port)}, nil
+}
+
+// pinNodeHTTPClient makes the client connect to the node API address
+// directly, whatever host the request names and whichever proxy is set.
+func pinNodeHTTPClient(client *http.Client, nodeAPI wgt.Endpoint) *http.Client {
+	var (
+		dialer    net.Dialer
+		transport = client.Transport.(*http.Transport)
+		addr      = net.JoinHostPort(nodeAPI.Host, strconv.Itoa(int(nodeAPI.Port)))
+	)
+
+	transport.Proxy = nil
+	transport.DialContext = func(c gocontext.Context, network, _ string) (net.Conn, error) {
+		return dialer.DialContext(c, network, addr)
+	}
+
+	return client
+}
+
+func postWithRetry(c gocontext.Context, client *http.Client, endpoint string, request []byte, attempts uint64) (resp *http.Response, err error) {
+	backoff := 500 * time.Millisecond
+	for i := uint64(0); i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-c.Done():
+				return nil, c.Err()
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+		}
+
+		var req *http.Request
+		req, err = http.NewRequestWithContext(c, http.MethodPost, endpoint, bytes.NewBuffer(request))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", jsonrpc.ContentType)
+
+		start := time.Now()
+		resp, err = client.Do(req)
+		metrics.ObserveNodeRequest(start)
+		if err != nil {
+			if c.Err() != nil {
+				return nil, c.Err()
+			}
+
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError && i+1 < attempts {
+			_ = resp.Body.Close()
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, err
+}
+
+// sessionResponseError is a node response that carries no session result,
+// with the status and code the start handler answers it with.
+type sessionResponseError struct {
+	status int
+	code   types.ErrorCode
+	cause  *types.Error
+	err    error
+}
+
+func (e *sessionResponseError) Error() string {
+	return e.err.Error()
+}
+
+// decodeSessionResponse returns the base64 encoded result of the response of
+// a node to a session request. A failed response may come with no error, and
+// a successful one with a result that is not a string.
+func decodeSessionResponse(r io.Reader) (string, error) {
+	var response types.Response
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return "", &sessionResponseError{http.StatusInternalServerError, ErrorSessionBadNodeResponse, nil, err}
+	}
+	if response.Error != nil {
+		return "", &sessionResponseError{http.StatusInternalServerError, ErrorSessionNodeRejected, response.Error,
+			fmt.Errorf("%s", response.Error.Message)}
+	}
+	if !response.Success {
+		return "", &sessionResponseError{http.StatusInternalServerError, ErrorSessionNodeRejected, nil,
+			fmt.Errorf("node request failed")}
+	}
+
+	data, ok := response.Result.(string)
+	if !ok {
+		return "", &sessionResponseError{http.StatusBadGateway, ErrorSessionBadNodeResult, nil,
+			fmt.Errorf("invalid node response result; expected string")}
+	}
+
+	return data, nil
+}
+
+// requestSession posts the key to the node and returns the decoded result. It
+// is used to reconnect, where there is no response to write errors to.
+func requestSession(c gocontext.Context, client *http.Client, endpoint, key string, attempts uint64, body *RequestAddSession) ([]byte, error) {
+	request, err := newSessionRequest(key, body.Hops)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := postWithRetry(c, client, endpoint, request, attempts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := decodeSessionResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResult(body.Type, body.Hops, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package session
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckCertificateFingerprint(t *testing.T) {
+	var (
+		onChain = bytes.Repeat([]byte{0xab}, 32)
+		other   = bytes.Repeat([]byte{0xcd}, 32)
+	)
+
+	tests := []struct {
+		name        string
+		expected    string
+		fingerprint []byte
+		err         bool
+	}{
+		{"none expected", "", nil, false},
+		{"none expected with one on chain", "", onChain, false},
+		{"expected without one on chain", hex.EncodeToString(onChain), nil, true},
+		{"match", hex.EncodeToString(onChain), onChain, false},
+		{"mismatch", hex.EncodeToString(other), onChain, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCertificateFingerprint(tt.expected, tt.fingerprint)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPostWithRetryCancelledMidFlight(t *testing.T) {
+	var (
+		started   = make(chan struct{})
+		cancelled = make(chan struct{})
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server notices the client going away only once the body is read.
+		_, _ = io.Copy(ioutil.Discard, r.Body)
+
+		close(started)
+		<-r.Context().Done()
+		close(cancelled)
+	}))
+	defer server.Close()
+
+	c, cancel := gocontext.WithCancel(gocontext.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	resp, err := postWithRetry(c, server.Client(), server.URL, []byte("{}"), 3)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if !errors.Is(err, gocontext.Canceled) {
+		t.Fatalf("expected %v, got %v", gocontext.Canceled, err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the node request was not cancelled")
+	}
+}
+
+func TestDecodeSessionResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		data     string
+		status   int
+		code     int
+		cause    bool
+	}{
+		{"result", `{"success":true,"result":"AAAA"}`, "AAAA", 0, 0, false},
+		{"failure without an error", `{"success":false,"error":null}`, "", http.StatusInternalServerError, ErrorSessionNodeRejected.Code, false},
+		{"failure without an error field", `{"success":false}`, "", http.StatusInternalServerError, ErrorSessionNodeRejected.Code, false},
+		{"failure with an error", `{"success":false,"error":{"code":5,"message":"subscription not found"}}`, "",
+			http.StatusInternalServerError, ErrorSessionNodeRejected.Code, true},
+		{"result that is not a string", `{"success":true,"result":42}`, "", http.StatusBadGateway, ErrorSessionBadNodeResult.Code, false},
+		{"success without a result", `{"success":true}`, "", http.StatusBadGateway, ErrorSessionBadNodeResult.Code, false},
+		{"not JSON", `<html>`, "", http.StatusInternalServerError, ErrorSessionBadNodeResponse.Code, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := decodeSessionResponse(strings.NewReader(tt.response))
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if data != tt.data {
+					t.Fatalf("expected result %q, got %q", tt.data, data)
+				}
+
+				return
+			}
+
+			var e *sessionResponseError
+			if !errors.As(err, &e) {
+				t.Fatalf("expected a session response error, got %v", err)
+			}
+			if e.status != tt.status || e.code.Code != tt.code {
+				t.Fatalf("expected status %d and code %d, got %d and %d", tt.status, tt.code, e.status, e.code.Code)
+			}
+			if (e.cause != nil) != tt.cause {
+				t.Fatalf("expected cause %v, got %v", tt.cause, e.cause)
+			}
+			if tt.cause && (e.cause.Code != 5 || e.Error() != "subscription not found") {
+				t.Fatalf("expected the node error code and message, got %d and %q", e.cause.Code, e.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+// Error codes returned by the session handlers. The numeric values are the
+// ones each handler has always used, so the same value can mean different
+// things in different handlers; the identifiers are safe to match on.
+
+// Error codes returned while starting a session.
+var (
+	ErrorSessionInvalidAddress      = types.NewErrorCode(1001, "SESSION_INVALID_ADDRESS")
+	ErrorSessionAddressMismatch     = types.NewErrorCode(1002, "SESSION_ADDRESS_MISMATCH")
+	ErrorSessionInvalidID           = types.NewErrorCode(1003, "SESSION_INVALID_ID")
+	ErrorSessionAlreadyActive       = types.NewErrorCode(1004, "SESSION_ALREADY_ACTIVE")
+	ErrorSessionInvalidRequest      = types.NewErrorCode(1005, "SESSION_INVALID_REQUEST")
+	ErrorSessionValidationFailed    = types.NewErrorCode(1006, "SESSION_VALIDATION_FAILED")
+	ErrorSessionFeatureConflict     = types.NewErrorCode(1007, "SESSION_FEATURE_CONFLICT")
+	ErrorSessionInvalidNodeAddress  = types.NewErrorCode(1008, "SESSION_INVALID_NODE_ADDRESS")
+	ErrorSessionQueryNodeFailed     = types.NewErrorCode(1009, "SESSION_QUERY_NODE_FAILED")
+	ErrorSessionNodeNotFound        = types.NewErrorCode(1010, "SESSION_NODE_NOT_FOUND")
+	ErrorSessionKeyGenerationFailed = types.NewErrorCode(1011, "SESSION_KEY_GENERATION_FAILED")
+	ErrorSessionBuildRequestFailed  = types.NewErrorCode(1012, "SESSION_BUILD_REQUEST_FAILED")
+	ErrorSessionNodeUnreachable     = types.NewErrorCode(1013, "SESSION_NODE_UNREACHABLE")
+	ErrorSessionBadNodeResponse     = types.NewErrorCode(1014, "SESSION_BAD_NODE_RESPONSE")
+	ErrorSessionNodeRejected        = types.NewErrorCode(1015, "SESSION_NODE_REJECTED")
+	ErrorSessionBadNodeResult       = types.NewErrorCode(1016, "SESSION_BAD_NODE_RESULT")
+	ErrorSessionBadNodeResultData   = types.NewErrorCode(1017, "SESSION_BAD_NODE_RESULT_DATA")
+	ErrorSessionInvalidNodeResult   = types.NewErrorCode(1018, "SESSION_INVALID_NODE_RESULT")
+	ErrorSessionBuildServiceFailed  = types.NewErrorCode(1019, "SESSION_BUILD_SERVICE_FAILED")
+	ErrorSessionConnectAborted      = types.NewErrorCode(1020, "SESSION_CONNECT_ABORTED")
+	ErrorSessionPreUpFailed         = types.NewErrorCode(1021, "SESSION_PRE_UP_FAILED")
+	ErrorSessionUpFailed            = types.NewErrorCode(1022, "SESSION_UP_FAILED")
+	ErrorSessionPostUpFailed        = types.NewErrorCode(1023, "SESSION_POST_UP_FAILED")
+	ErrorSessionSaveStatusFailed    = types.NewErrorCode(1024, "SESSION_SAVE_STATUS_FAILED")
+	ErrorSessionListenPortInUse     = types.NewErrorCode(1025, "SESSION_LISTEN_PORT_IN_USE")
+	ErrorSessionInvalidRemoteURL    = types.NewErrorCode(1026, "SESSION_INVALID_REMOTE_URL")
+	ErrorSessionRateLimited         = types.NewErrorCode(1027, "SESSION_RATE_LIMITED")
+	ErrorSessionRequestInProgress   = types.NewErrorCode(1028, "SESSION_REQUEST_IN_PROGRESS")
+	ErrorSessionConnectInProgress   = types.NewErrorCode(1029, "SESSION_CONNECT_IN_PROGRESS")
+	ErrorSessionResolveNodeFailed   = types.NewErrorCode(1030, "SESSION_RESOLVE_NODE_FAILED")
+	ErrorSessionCertificateMismatch = types.NewErrorCode(1031, "SESSION_CERTIFICATE_MISMATCH")
+)
+
+// Error codes returned while getting a session.
+var (
+	ErrorGetSessionInvalidID   = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorGetSessionQueryFailed = types.NewErrorCode(1002, "SESSION_QUERY_FAILED")
+)
+
+// Error codes returned while listing the sessions of an address.
+var (
+	ErrorGetSessionsForAddressInvalidAddress    = types.NewErrorCode(1001, "SESSION_INVALID_ADDRESS")
+	ErrorGetSessionsForAddressAddressMismatch   = types.NewErrorCode(1002, "SESSION_ADDRESS_MISMATCH")
+	ErrorGetSessionsForAddressInvalidPagination = types.NewErrorCode(1003, "SESSION_INVALID_PAGINATION")
+	ErrorGetSessionsForAddressInvalidSort       = types.NewErrorCode(1004, "SESSION_INVALID_SORT")
+	ErrorGetSessionsForAddressQueryFailed       = types.NewErrorCode(1005, "SESSION_QUERY_FAILED")
+)
+
+// Error codes returned while listing the sessions of a node.
+var (
+	ErrorGetSessionsForNodeInvalidNodeAddress = types.NewErrorCode(1001, "SESSION_INVALID_NODE_ADDRESS")
+	ErrorGetSessionsForNodeInvalidPagination  = types.NewErrorCode(1002, "SESSION_INVALID_PAGINATION")
+	ErrorGetSessionsForNodeInvalidSort        = types.NewErrorCode(1003, "SESSION_INVALID_SORT")
+	ErrorGetSessionsForNodeQueryFailed        = types.NewErrorCode(1004, "SESSION_QUERY_FAILED")
+)
+
+// Error codes returned while reconnecting to the last session, before the
+// start session flow takes over.
+var (
+	ErrorReconnectLoadFailed   = types.NewErrorCode(1001, "SESSION_LOAD_LAST_FAILED")
+	ErrorReconnectNoLast       = types.NewErrorCode(1002, "SESSION_NO_LAST_SESSION")
+	ErrorReconnectEncodeFailed = types.NewErrorCode(1003, "SESSION_ENCODE_LAST_FAILED")
+)
+
+// Error codes returned while cancelling a connect.
+var (
+	ErrorCancelConnectInvalidRequest   = types.NewErrorCode(1001, "SESSION_INVALID_REQUEST")
+	ErrorCancelConnectValidationFailed = types.NewErrorCode(1002, "SESSION_VALIDATION_FAILED")
+)
+
+// Error codes returned while stopping a session.
+var (
+	ErrorStopSessionInvalidID  = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorStopSessionNotActive  = types.NewErrorCode(1002, "SESSION_NOT_ACTIVE")
+	ErrorStopSessionStopFailed = types.NewErrorCode(1007, "SESSION_STOP_FAILED")
+)
+
+// Error codes returned while getting the status of a session.
+var (
+	ErrorGetSessionStatusInvalidID       = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorGetSessionStatusTransferFailed  = types.NewErrorCode(1002, "SESSION_TRANSFER_FAILED")
+	ErrorGetSessionStatusHandshakeFailed = types.NewErrorCode(1003, "SESSION_HANDSHAKE_FAILED")
+)
+
+// Error codes returned while getting the bandwidth samples of a session.
+var (
+	ErrorGetSessionBandwidthInvalidID = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+)
+
+// Error codes returned while rotating the key of a session.
+var (
+	ErrorRotateKeyInvalidID   = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorRotateKeyNotActive   = types.NewErrorCode(1002, "SESSION_NOT_ACTIVE")
+	ErrorRotateKeyUnsupported = types.NewErrorCode(1003, "SESSION_REKEY_UNSUPPORTED")
+	ErrorRotateKeyFailed      = types.NewErrorCode(1004, "SESSION_REKEY_FAILED")
+)
+
+// Error codes returned while getting the config of a session.
+var (
+	ErrorGetSessionConfigInvalidID     = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorGetSessionConfigInvalidRedact = types.NewErrorCode(1002, "SESSION_INVALID_REDACT")
+	ErrorGetSessionConfigNotActive     = types.NewErrorCode(1003, "SESSION_NOT_ACTIVE")
+	ErrorGetSessionConfigReadFailed    = types.NewErrorCode(1004, "SESSION_READ_CONFIG_FAILED")
+)
+
+// Error codes returned while streaming the events of a session, the last one
+// over the websocket.
+var (
+	ErrorGetSessionEventsInvalidID       = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorGetSessionEventsInvalidInterval = types.NewErrorCode(1002, "SESSION_INVALID_INTERVAL")
+	ErrorGetSessionEventsNotActive       = types.NewErrorCode(1003, "SESSION_NOT_ACTIVE")
+	ErrorGetSessionEventsTransferFailed  = types.NewErrorCode(1004, "SESSION_TRANSFER_FAILED")
+)
+
+// Error codes returned while running a speed test through a session.
+var (
+	ErrorSpeedTestInvalidID        = types.NewErrorCode(1001, "SESSION_INVALID_ID")
+	ErrorSpeedTestInvalidRequest   = types.NewErrorCode(1002, "SESSION_INVALID_REQUEST")
+	ErrorSpeedTestValidationFailed = types.NewErrorCode(1003, "SESSION_VALIDATION_FAILED")
+	ErrorSpeedTestNotActive        = types.NewErrorCode(1004, "SESSION_NOT_ACTIVE")
+	ErrorSpeedTestFailed           = types.NewErrorCode(1005, "SESSION_SPEED_TEST_FAILED")
+)
+
+// Error codes returned while listing the active sessions.
+var (
+	ErrorGetActiveSessionsDecodeFailed = types.NewErrorCode(1001, "SESSION_DECODE_STATUS_FAILED")
+)
+
+// Error codes returned while checking for a DNS leak.
+var (
+	ErrorCheckDNSLeakLookupFailed = types.NewErrorCode(1001, "SESSION_DNS_LOOKUP_FAILED")
+)
+
+// Error codes returned while resolving a name.
+var (
+	ErrorResolveInvalidName = types.NewErrorCode(1001, "SESSION_INVALID_NAME")
+	ErrorResolveFailed      = types.NewErrorCode(1002, "SESSION_RESOLVE_FAILED")
+)
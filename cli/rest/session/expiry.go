@@ -0,0 +1,59 @@
+package session
+
+import (
+	"log"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+// WatchExpiry stops the session of the subscription once the account has no
+// active session on it, as happens when the session or the subscription
+// expires. It is exported so that the sessions restored at startup are
+// watched too.
+func WatchExpiry(ctx *context.Context, id uint64, service types.Service) {
+	interval := time.Duration(ctx.Config().Session.ExpiryInterval) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Context().Done():
+				return
+			case <-ticker.C:
+			}
+
+			if ctx.Service(id) != service {
+				return
+			}
+
+			// Services are keyed by subscription, so the session is looked up
+			// as the active one of the account on it.
+			res, err := ctx.Client().QuerySessionForSubscription(id, ctx.Client().FromAddress())
+			if err != nil {
+				log.Printf("Failed to query the session of subscription %d: %s", id, err)
+				continue
+			}
+			if res != nil {
+				continue
+			}
+
+			reason := "session is no longer active on chain"
+			if wg, ok := service.(*wireguard.WireGuard); ok {
+				wg.Emit(wgt.NewEvent(wgt.EventSessionExpired).WithReason(reason))
+			}
+
+			log.Printf("Stopping the session of subscription %d: %s", id, reason)
+			if err := StopSession(ctx, id, service); err != nil {
+				log.Printf("Failed to stop the session of subscription %d: %s", id, err)
+			}
+
+			return
+		}
+	}()
+}
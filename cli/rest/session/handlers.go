@@ -2,31 +2,42 @@ package session
 
 import (
 	"bytes"
-	"crypto/tls"
+	gocontext "context"
 	"encoding/base64"
-	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
 	"net"
 	"net/http"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/go-kit/kit/transport/http/jsonrpc"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 	hubtypes "github.com/sentinel-official/hub/types"
 
 	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/metrics"
+	"github.com/sentinel-official/desktop-client/cli/services/v2ray"
+	v2raytypes "github.com/sentinel-official/desktop-client/cli/services/v2ray/types"
 	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
 	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
 	"github.com/sentinel-official/desktop-client/cli/types"
 	"github.com/sentinel-official/desktop-client/cli/utils"
+	"github.com/sentinel-official/desktop-client/cli/x/common"
 	"github.com/sentinel-official/desktop-client/cli/x/session"
 )
 
+const (
+	idempotencyTTL = 10 * time.Minute
+)
+
 func HandlerGetSession(ctx *context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -35,13 +46,13 @@ func HandlerGetSession(ctx *context.Context) http.HandlerFunc {
 
 		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionInvalidID, err.Error())
 			return
 		}
 
 		res, err := ctx.Client().QuerySession(id)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionQueryFailed, err.Error())
 			return
 		}
 
@@ -58,225 +69,943 @@ func HandlerGetSessionsForAddress(ctx *context.Context) http.HandlerFunc {
 			status = hubtypes.StatusFromString(values.Get("status"))
 		)
 
-		address, err := sdk.AccAddressFromBech32(vars["address"])
+		address, err := utils.ParseAccAddress(vars["address"])
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForAddressInvalidAddress, err.Error())
 			return
 		}
 		if !ctx.Client().FromAddress().Equals(address) {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, "")
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForAddressAddressMismatch, "")
 			return
 		}
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForAddressInvalidPagination, err.Error())
+			return
+		}
+
+		if pagination.Key != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForAddressInvalidPagination, "invalid query key; sorted sessions are paged by offset")
 			return
 		}
 
-		res, err := ctx.Client().QuerySessionsForAddress(address, status, pagination)
+		sort, err := utils.ParseSortQuery(values, "-id", "id", "bandwidth")
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForAddressInvalidSort, err.Error())
+			return
+		}
+
+		// The chain returns sessions in the order of its store, so all of them
+		// are fetched to sort before the page is cut.
+		res, err := ctx.Client().QueryAllSessionsForAddress(address, status)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionsForAddressQueryFailed, err.Error())
 			return
 		}
 
 		items := session.NewSessionsFromRaw(res)
-		utils.WriteResultToResponse(w, http.StatusOK, items)
+		items.Sort(sort)
+
+		item := newResponseSessions(items, pagination.Offset, pagination.Limit)
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerGetSessionsForNode(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			values = r.URL.Query()
+			vars   = mux.Vars(r)
+		)
+
+		address, err := hubtypes.NodeAddressFromBech32(vars["address"])
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForNodeInvalidNodeAddress, fmt.Sprintf("invalid node address %s; %s", vars["address"], err))
+			return
+		}
+
+		pagination, err := utils.ParsePaginationQuery(values)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForNodeInvalidPagination, err.Error())
+			return
+		}
+
+		if pagination.Key != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForNodeInvalidPagination, "invalid query key; sorted sessions are paged by offset")
+			return
+		}
+
+		sort, err := utils.ParseSortQuery(values, "-id", "id", "bandwidth")
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionsForNodeInvalidSort, err.Error())
+			return
+		}
+
+		res, err := ctx.Client().QueryAllSessionsForNode(address)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionsForNodeQueryFailed, err.Error())
+			return
+		}
+
+		items := session.NewSessionsFromRaw(res)
+		items.Sort(sort)
+
+		item := newResponseSessions(items, pagination.Offset, pagination.Limit)
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
 	}
 }
 
 func HandlerStartSession(ctx *context.Context) http.HandlerFunc {
-	var (
-		client = http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
+	return func(w http.ResponseWriter, r *http.Request) {
+		startSession(ctx, w, r, mux.Vars(r))
+	}
+}
+
+func HandlerReconnect(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		last, err := loadLastSession(ctx.Home())
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorReconnectLoadFailed, err.Error())
+			return
+		}
+		if last == nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusNotFound, ErrorReconnectNoLast, "no previous session to reconnect to")
+			return
+		}
+
+		data, err := json.Marshal(last.Request)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorReconnectEncodeFailed, err.Error())
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		startSession(ctx, w, r,
+			map[string]string{
+				"address": last.Address,
+				"id":      strconv.FormatUint(last.ID, 10),
 			},
-			Timeout: 5 * time.Second,
+		)
+	}
+}
+
+// startSession runs the start session flow for the account address and
+// subscription id in vars, with the request body read from r.
+func startSession(ctx *context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var (
+		success = false
+	)
+
+	defer func() {
+		metrics.ObserveSessionAdd(success)
+	}()
+
+	address, err := utils.ParseAccAddress(vars["address"])
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionInvalidAddress, err.Error())
+		return
+	}
+	if !ctx.Client().FromAddress().Equals(address) {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionAddressMismatch, "")
+		return
+	}
+
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionInvalidID, err.Error())
+		return
+	}
+
+	// A retry carrying the Idempotency-Key of a connect that succeeded gets its
+	// result again, instead of the session being provisioned a second time.
+	var replay interface{}
+	if v := r.Header.Get("Idempotency-Key"); v != "" {
+		v = r.URL.Path + " " + v
+
+		cached, ok, err := ctx.BeginIdempotent(v)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusConflict, ErrorSessionRequestInProgress, err.Error())
+			return
+		}
+		if ok {
+			utils.WriteResultToResponse(w, http.StatusOK, cached)
+			return
+		}
+
+		defer func() {
+			ctx.EndIdempotent(v, replay, idempotencyTTL)
+		}()
+	}
+
+	if ctx.Service(id) != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionAlreadyActive, "session is already active")
+		return
+	}
+
+	c, cancel := gocontext.WithCancel(r.Context())
+	defer cancel()
+
+	if !ctx.BeginConnect(id, cancel) {
+		utils.WriteCodedErrorToResponse(w, http.StatusConflict, ErrorSessionConnectInProgress, "a connect for this session is already in progress")
+		return
+	}
+
+	defer ctx.EndConnect(id)
+
+	body, err := NewRequestAddSession(r)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionInvalidRequest, err.Error())
+		return
+	}
+	if err := body.Validate(); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionValidationFailed, err.Error())
+		return
+	}
+	if err := checkExclusiveFeatures(ctx, body); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionFeatureConflict, err.Error())
+		return
+	}
+	if body.ListenPort != nil {
+		if err := utils.CheckUDPPort(uint16(*body.ListenPort)); err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusConflict, ErrorSessionListenPortInUse, err.Error())
+			return
 		}
+	}
+
+	to, err := hex.DecodeString(body.To)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionInvalidNodeAddress, err.Error())
+		return
+	}
+
+	if ok, wait := ctx.SessionLimiter().Allow(hex.EncodeToString(to)); !ok {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10))
+		utils.WriteCodedErrorToResponse(w, http.StatusTooManyRequests, ErrorSessionRateLimited, "too many session requests for this node")
+		return
+	}
+
+	node, err := ctx.Client().QueryNode(to)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionQueryNodeFailed, err.Error())
+		return
+	}
+	if node == nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionNodeNotFound, "")
+		return
+	}
+
+	remoteURL, fingerprint, err := utils.SplitNodeRemoteURL(node.RemoteURL)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionInvalidRemoteURL, err.Error())
+		return
+	}
+	if err := validateRemoteURL(remoteURL); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionInvalidRemoteURL, err.Error())
+		return
+	}
+	if err := checkCertificateFingerprint(body.CertificateFingerprint, fingerprint); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSessionCertificateMismatch, err.Error())
+		return
+	}
+
+	if body.Type == "" {
+		body.Type = types.ServiceTypeWireGuard
+	}
+
+	var (
+		key        string
+		privateKey *wgt.Key
+		uuid       *v2raytypes.UUID
 	)
 
-	return func(w http.ResponseWriter, r *http.Request) {
-		var (
-			service = ctx.Service()
-			vars    = mux.Vars(r)
-		)
+	defer func() {
+		if privateKey != nil {
+			privateKey.Zero()
+		}
+	}()
 
-		if service != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, "")
+	switch body.Type {
+	case types.ServiceTypeV2Ray:
+		uuid, err = v2raytypes.NewUUID()
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionKeyGenerationFailed, err.Error())
 			return
 		}
 
-		address, err := sdk.AccAddressFromBech32(vars["address"])
+		key = base64.StdEncoding.EncodeToString(uuid[:])
+	default:
+		privateKey, err = wgt.NewPrivateKey()
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionKeyGenerationFailed, err.Error())
 			return
 		}
-		if !ctx.Client().FromAddress().Equals(address) {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1003, "")
+
+		key = privateKey.Public().String()
+	}
+
+	request, err := newSessionRequest(key, body.Hops)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionBuildRequestFailed, err.Error())
+		return
+	}
+
+	var (
+		endpoint = fmt.Sprintf("%s/accounts/%s/subscriptions/%d/sessions", strings.TrimSuffix(remoteURL, "/"), address, id)
+	)
+
+	attempts := body.Attempts
+	if attempts == 0 {
+		attempts = 3
+	}
+
+	resp, err := postWithRetry(c, newNodeHTTPClient(ctx, body, fingerprint), endpoint, request, attempts)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionNodeUnreachable, err.Error())
+		return
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := decodeSessionResponse(resp.Body)
+	if err != nil {
+		var e *sessionResponseError
+		if !errors.As(err, &e) {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadGateway, ErrorSessionBadNodeResponse, err.Error())
 			return
 		}
 
-		id, err := strconv.ParseUint(vars["id"], 10, 64)
+		utils.WriteCodedErrorWithCauseToResponse(w, e.status, e.code, e.Error(), e.cause)
+		return
+	}
+
+	result, err := base64.StdEncoding.DecodeString(data)
+	logNodeResult(ctx, node.Address, body, data, result, err)
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadGateway, ErrorSessionBadNodeResultData, err.Error())
+		return
+	}
+	if err := checkResult(body.Type, body.Hops, result); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusBadGateway, ErrorSessionInvalidNodeResult, err.Error())
+		return
+	}
+
+	status := types.NewStatus().
+		WithDNSGuard(body.DNSGuard).
+		WithFrom(ctx.Client().FromAddress().String()).
+		WithID(id).
+		WithKillSwitch(body.KillSwitch).
+		WithRemoteURL(node.RemoteURL).
+		WithTo(body.To).
+		WithType(body.Type)
+
+	var service types.Service
+	switch body.Type {
+	case types.ServiceTypeV2Ray:
+		service, err = newV2RayService(ctx, body, status, uuid, result)
+	default:
+		service, err = newWireGuardService(ctx, body, status, privateKey, result)
+	}
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionBuildServiceFailed, err.Error())
+		return
+	}
+
+	// A dry run stops once the config is built, leaving the interface and
+	// the status file untouched.
+	if body.DryRun {
+		item := newResponseStartSession(id, service)
+		item.DryRun = true
+		if wg, ok := service.(*wireguard.WireGuard); ok {
+			item.Config = wg.Config().ToWgQuickRedacted()
+			wg.Config().Interface.PrivateKey.Zero()
+		}
+
+		success = true
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+		return
+	}
+
+	if err := c.Err(); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusRequestTimeout, ErrorSessionConnectAborted, err.Error())
+		return
+	}
+
+	// Whatever fails or is cancelled once the interface is up takes it down
+	// again, rather than leaving it up without a session to stop it by.
+	up := false
+	defer func() {
+		if !up || success {
+			return
+		}
+
+		if err := utils.StopService(service); err != nil {
+			log.Printf("Failed to clean up session %d: %s", id, err)
+		}
+	}()
+
+	// The node API is resolved while the system resolver still works, as
+	// the reconnect requests go to it outside the tunnel.
+	var nodeAPI wgt.Endpoint
+	if wg, ok := service.(*wireguard.WireGuard); ok {
+		nodeAPI, err = resolveNodeAPI(c, ctx.Resolver(), endpoint)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1004, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadGateway, ErrorSessionResolveNodeFailed, err.Error())
 			return
 		}
 
-		body, err := NewRequestAddSession(r)
+		wg.WithNodeAPI(nodeAPI)
+	}
+
+	if err := service.PreUp(); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionPreUpFailed, err.Error())
+		return
+	}
+	if wg, ok := service.(*wireguard.WireGuard); ok {
+		attempts := 3
+		if body.ListenPort != nil {
+			attempts = 1
+		}
+
+		err = upWireGuard(c, wg, attempts)
+	} else {
+		err = service.Up()
+	}
+	if err != nil && c.Err() != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusRequestTimeout, ErrorSessionConnectAborted, err.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionUpFailed, err.Error())
+		return
+	}
+
+	up = true
+	if err := service.PostUp(); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionPostUpFailed, err.Error())
+		return
+	}
+	if err := c.Err(); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusRequestTimeout, ErrorSessionConnectAborted, err.Error())
+		return
+	}
+
+	// The key is in the config file by now, which is what wg-quick down reads.
+	if wg, ok := service.(*wireguard.WireGuard); ok {
+		wg.Config().Interface.PrivateKey.Zero()
+	}
+
+	if err := status.SaveToPath(types.StatusFilePath(ctx.Home(), id)); err != nil {
+		utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSessionSaveStatusFailed, err.Error())
+		return
+	}
+
+	last := &lastSession{
+		Address:   address.String(),
+		ID:        id,
+		RemoteURL: node.RemoteURL,
+		Request:   *body,
+	}
+
+	if err := saveLastSession(ctx.Home(), last); err != nil {
+		log.Printf("Failed to save session %d for reconnecting: %s", id, err)
+	}
+
+	if wg, ok := service.(*wireguard.WireGuard); ok {
+		wg.WithReconnect(newWireGuardReconnectFunc(ctx, body, status, endpoint, fingerprint, nodeAPI))
+		if cfg := ctx.Config().Reconnect; cfg.Enabled {
+			wg.StartMonitor(
+				wireguard.MonitorOptions{
+					Interval:  time.Duration(cfg.Interval) * time.Second,
+					Threshold: time.Duration(cfg.StaleThreshold) * time.Second,
+					Attempts:  cfg.Attempts,
+					Backoff:   5 * time.Second,
+				},
+			)
+		}
+	}
+
+	ctx = ctx.WithService(id, service)
+	ctx.StartBandwidthSampler(id, service)
+	WatchExpiry(ctx, id, service)
+	if body.MaxBytes != nil {
+		watchQuota(ctx, id, service, *body.MaxBytes)
+	}
+
+	ctx.NotifyWebhook(context.WebhookEventSessionStarted, id, service)
+	success = true
+	replay = newResponseStartSession(id, service)
+	utils.WriteResultToResponse(w, http.StatusOK, replay)
+}
+
+func HandlerCancelConnect(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := NewRequestCancelConnect(r)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1005, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorCancelConnectInvalidRequest, err.Error())
 			return
 		}
 		if err := body.Validate(); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1006, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorCancelConnectValidationFailed, err.Error())
 			return
 		}
 
-		to, err := hex.DecodeString(body.To)
+		item := ResponseCancelConnect{
+			ID:      body.ID,
+			Aborted: ctx.CancelConnect(body.ID),
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerStopSession(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
+		)
+
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1007, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorStopSessionInvalidID, err.Error())
+			return
+		}
+
+		service := ctx.Service(id)
+		if service == nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusNotFound, ErrorStopSessionNotActive, "no active session")
+			return
+		}
+
+		if err := StopSession(ctx, id, service); err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorStopSessionStopFailed, err.Error())
 			return
 		}
 
-		node, err := ctx.Client().QueryNode(to)
+		utils.WriteResultToResponse(w, http.StatusOK, nil)
+	}
+}
+
+func HandlerGetSessionStatus(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
+		)
+
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1008, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionStatusInvalidID, err.Error())
 			return
 		}
-		if node.Address == "" {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1009, "")
+
+		service := ctx.Service(id)
+		if service == nil {
+			utils.WriteResultToResponse(w, http.StatusOK, ResponseSessionStatus{})
 			return
 		}
 
-		privateKey, err := wgt.NewPrivateKey()
+		download, upload, err := service.Transfer()
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1010, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionStatusTransferFailed, err.Error())
 			return
 		}
 
-		request, err := json.Marshal(
-			map[string]interface{}{
-				"key": privateKey.Public().String(),
-			},
+		item := ResponseSessionStatus{
+			Upload:    upload,
+			Download:  download,
+			Connected: true,
+		}
+
+		if wg, ok := service.(*wireguard.WireGuard); ok {
+			item.LatestHandshake, err = wg.LatestHandshake()
+			if err != nil {
+				utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionStatusHandshakeFailed, err.Error())
+				return
+			}
+
+			item.Connected = !item.LatestHandshake.IsZero()
+		}
+		if item.Connected {
+			item.InternetReachable = isInternetReachable(r.Context(), ctx, id, service)
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerGetSessionBandwidth(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
 		)
+
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1011, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionBandwidthInvalidID, err.Error())
 			return
 		}
 
+		item := ResponseSessionBandwidth{
+			Active:   ctx.Service(id) != nil,
+			Interval: ctx.Config().Bandwidth.Interval,
+			Samples:  []types.BandwidthSample{},
+		}
+
+		if series := ctx.BandwidthSeries(id); series != nil {
+			item.Samples = series.Samples()
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerRotateKey(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			response types.Response
-			endpoint = fmt.Sprintf("%s/accounts/%s/subscriptions/%d/sessions", node.RemoteURL, address, id)
+			vars = mux.Vars(r)
 		)
 
-		resp, err := client.Post(endpoint, jsonrpc.ContentType, bytes.NewBuffer(request))
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1012, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorRotateKeyInvalidID, err.Error())
 			return
 		}
 
-		defer func() {
-			_ = resp.Body.Close()
-		}()
+		service := ctx.Service(id)
+		if service == nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusNotFound, ErrorRotateKeyNotActive, "no active session")
+			return
+		}
 
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1013, err.Error())
+		wg, ok := service.(*wireguard.WireGuard)
+		if !ok {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorRotateKeyUnsupported, "only WireGuard sessions can be rekeyed")
 			return
 		}
-		if !response.Success || response.Error != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1014, "")
+
+		changed, err := wg.Rekey(r.Context())
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorRotateKeyFailed, err.Error())
 			return
 		}
 
-		result, err := base64.StdEncoding.DecodeString(response.Result.(string))
+		item := ResponseRotateKey{
+			ResponseStartSession: newResponseStartSession(id, service),
+			AddressesChanged:     changed,
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerGetSessionConfig(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			values = r.URL.Query()
+			vars   = mux.Vars(r)
+			redact = true
+		)
+
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1015, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionConfigInvalidID, err.Error())
 			return
 		}
-		if len(result) != 58 {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1016, "")
+
+		if values.Get("redact") != "" {
+			redact, err = strconv.ParseBool(values.Get("redact"))
+			if err != nil {
+				utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionConfigInvalidRedact, "invalid query redact")
+				return
+			}
+		}
+
+		service := ctx.Service(id)
+		if service == nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusNotFound, ErrorGetSessionConfigNotActive, "no active session")
 			return
 		}
 
+		item := ResponseSessionConfig{
+			Redacted: redact,
+		}
+
+		switch s := service.(type) {
+		case *wireguard.WireGuard:
+			cfg := s.Config()
+			item.Name, item.Type, item.Config = cfg.Name, types.ServiceTypeWireGuard, cfg.ToWgQuickRedacted()
+
+			// The private key is zeroed in memory once the interface is up, so
+			// the unredacted config comes from the file wg-quick was given.
+			if !redact {
+				data, err := ioutil.ReadFile(filepath.Join(ctx.Home(), cfg.Name+".conf"))
+				if err != nil {
+					utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionConfigReadFailed, err.Error())
+					return
+				}
+
+				item.Config = string(data)
+			}
+		case *v2ray.V2Ray:
+			cfg := s.Config()
+			item.Name, item.Type = cfg.Name, types.ServiceTypeV2Ray
+
+			item.Config, err = cfg.ToJSON()
+			if err != nil {
+				utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetSessionConfigReadFailed, err.Error())
+				return
+			}
+			if redact {
+				item.Config = strings.ReplaceAll(item.Config, cfg.VMess.ID.String(), "<redacted>")
+			}
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerGetSessionEvents(ctx *context.Context) http.HandlerFunc {
+	var (
+		upgrader = websocket.Upgrader{
+			// Browsers are held to the origins CORS allows, while clients that
+			// send no origin are let through, as the token guards them.
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || utils.IsOriginAllowed(ctx.Config().CORS.AllowedOrigins, origin)
+			},
+		}
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
 		var (
-			v4Addr, v6Addr = net.IP(result[0:4]), net.IP(result[4:20])
-			host, port     = net.IP(result[20:24]), binary.BigEndian.Uint16(result[24:26])
-			publicKey      = wgt.NewKey(result[26:58])
+			values   = r.URL.Query()
+			vars     = mux.Vars(r)
+			interval = 1 * time.Second
 		)
 
-		listenPort, err := utils.GetFreeUDPPort()
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1017, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionEventsInvalidID, err.Error())
 			return
 		}
 
-		cfg := &wgt.Config{
-			Name: wgt.DefaultInterface,
-			Interface: wgt.Interface{
-				Addresses: []wgt.IPNet{
-					{v4Addr, 32},
-					{v6Addr, 128},
-				},
-				ListenPort: listenPort,
-				PrivateKey: *privateKey,
-				DNS: []net.IP{
-					net.ParseIP("10.8.0.1"),
-				},
-			},
-			Peers: []wgt.Peer{
-				{
-					PublicKey: *publicKey,
-					AllowedIPs: []wgt.IPNet{
-						{net.ParseIP("0.0.0.0"), 0},
-						{net.ParseIP("::"), 0},
-					},
-					Endpoint: wgt.Endpoint{
-						Host: host.String(),
-						Port: port,
+		if values.Get("interval") != "" {
+			seconds, err := strconv.ParseUint(values.Get("interval"), 10, 64)
+			if err != nil || seconds == 0 {
+				utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorGetSessionEventsInvalidInterval, "invalid query interval")
+				return
+			}
+
+			interval = time.Duration(seconds) * time.Second
+		}
+
+		service := ctx.Service(id)
+		if service == nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusNotFound, ErrorGetSessionEventsNotActive, "no active session")
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		var events <-chan wgt.Event
+		if wg, ok := service.(*wireguard.WireGuard); ok {
+			var cancel func()
+			events, cancel = wg.Subscribe()
+			defer cancel()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case event := <-events:
+				if err := conn.WriteJSON(types.Response{
+					Success: true,
+					Result:  event,
+				}); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if ctx.Service(id) != service {
+					_ = conn.WriteMessage(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseNormalClosure, "session stopped"))
+					return
+				}
+
+				download, upload, err := service.Transfer()
+				if err != nil {
+					_ = conn.WriteJSON(types.Response{
+						Success: false,
+						Error: types.NewError("", ErrorGetSessionEventsTransferFailed.Code, err.Error()).
+							WithID(ErrorGetSessionEventsTransferFailed.ID),
+					})
+					continue
+				}
+
+				if err := conn.WriteJSON(types.Response{
+					Success: true,
+					Result: common.Bandwidth{
+						Upload:   upload,
+						Download: download,
 					},
-					PersistentKeepalive: 15,
-				},
-			},
+				}); err != nil {
+					return
+				}
+			}
 		}
+	}
+}
 
-		status := types.NewStatus().
-			WithFrom(ctx.Client().FromAddress().String()).
-			WithID(id).
-			WithName(cfg.Name).
-			WithTo(body.To)
+func HandlerSpeedTest(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			vars = mux.Vars(r)
+		)
 
-		info, err := json.Marshal(status)
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1018, err.Error())
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSpeedTestInvalidID, err.Error())
 			return
 		}
 
-		service = wireguard.NewWireGuard().
-			WithConfig(cfg).
-			WithConfigDir(ctx.Home()).
-			WithInfo(info)
+		body, err := NewRequestSpeedTest(r)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSpeedTestInvalidRequest, err.Error())
+			return
+		}
+		if err := body.Validate(); err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorSpeedTestValidationFailed, err.Error())
+			return
+		}
 
-		if err := service.PreUp(); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1019, err.Error())
+		service := ctx.Service(id)
+		if service == nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusNotFound, ErrorSpeedTestNotActive, "no active session")
 			return
 		}
-		if err := service.Up(); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1020, err.Error())
+
+		item, err := runSpeedTest(r.Context(), service, body)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorSpeedTestFailed, err.Error())
 			return
 		}
-		if err := service.PostUp(); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1021, err.Error())
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerGetActiveSessions(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ids   = ctx.ServiceIDs()
+			items = make([]ResponseActiveSession, 0, len(ids))
+		)
+
+		for _, id := range ids {
+			service := ctx.Service(id)
+			if service == nil {
+				continue
+			}
+
+			var status types.Status
+			if err := json.Unmarshal(service.Info(), &status); err != nil {
+				utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorGetActiveSessionsDecodeFailed, err.Error())
+				return
+			}
+
+			items = append(items,
+				ResponseActiveSession{
+					ID:        status.ID,
+					Node:      status.To,
+					RemoteURL: status.RemoteURL,
+					Name:      status.Name,
+					Type:      status.Type,
+				},
+			)
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, items)
+	}
+}
+
+func HandlerCheckDNSLeak(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			item ResponseDNSLeak
+		)
+
+		for _, id := range ctx.ServiceIDs() {
+			if wg, ok := ctx.Service(id).(*wireguard.WireGuard); ok {
+				for _, dns := range wg.Config().Interface.DNS {
+					item.TunnelDNS = append(item.TunnelDNS, dns.String())
+				}
+			}
+		}
+
+		resolvers, err := net.DefaultResolver.LookupHost(r.Context(), "whoami.akamai.net")
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusInternalServerError, ErrorCheckDNSLeakLookupFailed, err.Error())
 			return
 		}
 
-		if err := status.SaveToPath(filepath.Join(ctx.Home(), "status.json")); err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1022, err.Error())
+		item.Resolvers = resolvers
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
+
+func HandlerResolve(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadRequest, ErrorResolveInvalidName, "invalid query name; expected non-empty value")
 			return
 		}
 
-		ctx = ctx.WithService(service)
-		utils.WriteResultToResponse(w, http.StatusOK, nil)
+		item := ResponseResolve{
+			Name:     name,
+			Resolver: "system",
+			Servers:  []string{},
+		}
+
+		servers := ctx.TunnelDNS()
+		if len(servers) > 0 {
+			item.Resolver = "tunnel"
+			for _, server := range servers {
+				item.Servers = append(item.Servers, server.String())
+			}
+		}
+
+		addresses, err := utils.NewResolver(servers).LookupHost(r.Context(), name)
+		if err != nil {
+			utils.WriteCodedErrorToResponse(w, http.StatusBadGateway, ErrorResolveFailed, err.Error())
+			return
+		}
+
+		item.Addresses = addresses
+		utils.WriteResultToResponse(w, http.StatusOK, item)
 	}
 }
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func TestHandlerErrorCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(ctx *context.Context) http.HandlerFunc
+		target  string
+		vars    map[string]string
+		status  int
+		code    types.ErrorCode
+	}{
+		{"get session invalid id", HandlerGetSession, "/", map[string]string{"id": "x"}, http.StatusBadRequest, ErrorGetSessionInvalidID},
+		{"stop session invalid id", HandlerStopSession, "/", map[string]string{"id": "x"}, http.StatusBadRequest, ErrorStopSessionInvalidID},
+		{"stop session not active", HandlerStopSession, "/", map[string]string{"id": "1"}, http.StatusNotFound, ErrorStopSessionNotActive},
+		{"rotate key not active", HandlerRotateKey, "/", map[string]string{"id": "1"}, http.StatusNotFound, ErrorRotateKeyNotActive},
+		{"config invalid redact", HandlerGetSessionConfig, "/?redact=x", map[string]string{"id": "1"}, http.StatusBadRequest, ErrorGetSessionConfigInvalidRedact},
+		{"events invalid interval", HandlerGetSessionEvents, "/?interval=0", map[string]string{"id": "1"}, http.StatusBadRequest, ErrorGetSessionEventsInvalidInterval},
+		{"resolve invalid name", HandlerResolve, "/", nil, http.StatusBadRequest, ErrorResolveInvalidName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				w = httptest.NewRecorder()
+				r = mux.SetURLVars(httptest.NewRequest(http.MethodGet, tt.target, nil), tt.vars)
+			)
+
+			tt.handler(newTestContext(t)).ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+
+			var res types.Response
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Error == nil || res.Error.Code != tt.code.Code || res.Error.ID != tt.code.ID {
+				t.Fatalf("expected error %d %s, got %+v", tt.code.Code, tt.code.ID, res.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package session
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	lastSessionFileName = "last-session.json"
+)
+
+// lastSession records the latest session started, so that it can be started
+// again after it is stopped, when its status file is gone.
+type lastSession struct {
+	Address   string            `json:"address"`
+	ID        uint64            `json:"id"`
+	RemoteURL string            `json:"remote_url"`
+	Request   RequestAddSession `json:"request"`
+}
+
+func saveLastSession(home string, v *lastSession) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(home, lastSessionFileName), data, 0600)
+}
+
+func loadLastSession(home string) (*lastSession, error) {
+	data, err := ioutil.ReadFile(filepath.Join(home, lastSessionFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var v lastSession
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+const (
+	maxHops = 3
+)
+
+type wireGuardPeer struct {
+	host         net.IP
+	port         uint16
+	publicKey    wgt.Key
+	presharedKey wgt.Key
+	allowedIPs   []wgt.IPNet
+}
+
+type resultReader struct {
+	data []byte
+	err  error
+}
+
+func (r *resultReader) next(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.data) < n {
+		r.err = fmt.Errorf("invalid node response result; unexpected end of data")
+		return make([]byte, n)
+	}
+
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *resultReader) ip() net.IP {
+	switch n := int(r.next(1)[0]); n {
+	case net.IPv4len, net.IPv6len:
+		return net.IP(r.next(n))
+	default:
+		if r.err == nil {
+			r.err = fmt.Errorf("invalid node response result; invalid IP length %d", n)
+		}
+
+		return nil
+	}
+}
+
+// ipNets reads a count of networks followed by the networks, each as
+// IP length (1) | IP (4 or 16) | prefix length (1).
+func (r *resultReader) ipNets() []wgt.IPNet {
+	var items []wgt.IPNet
+	for i := int(r.next(1)[0]); i > 0 && r.err == nil; i-- {
+		ip := r.ip()
+		prefix := r.next(1)[0]
+		if r.err == nil && int(prefix) > 8*len(ip) {
+			r.err = fmt.Errorf("invalid node response result; invalid prefix length %d", prefix)
+		}
+
+		items = append(items, wgt.IPNet{IP: ip, Net: prefix})
+	}
+
+	return items
+}
+
+// decodeMultiHopResult decodes the result a node returns for a session with
+// more than one hop, laid out as
+//
+//	IPv4 address (4) | IPv6 address (16) | peer count (1) | peers
+//
+// with each peer, entry first, as
+//
+//	host length (1) | host (4 or 16) | port (2) | public key (32) |
+//	has preshared key (1) | [preshared key (32)] | allowed IP count (1) |
+//	allowed IPs, each as IP length (1) | IP (4 or 16) | prefix length (1)
+func decodeMultiHopResult(result []byte, hops uint64) (v4Addr, v6Addr net.IP, peers []wireGuardPeer, err error) {
+	r := &resultReader{data: result}
+
+	v4Addr, v6Addr = net.IP(r.next(net.IPv4len)), net.IP(r.next(net.IPv6len))
+	count := uint64(r.next(1)[0])
+	if r.err == nil && count != hops {
+		return nil, nil, nil, fmt.Errorf("invalid node response result; expected %d peers, got %d", hops, count)
+	}
+
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		var peer wireGuardPeer
+
+		peer.host = r.ip()
+		peer.port = binary.BigEndian.Uint16(r.next(2))
+		peer.publicKey = *wgt.NewKey(r.next(wgt.KeyLength))
+		if r.next(1)[0] != 0 {
+			peer.presharedKey = *wgt.NewKey(r.next(wgt.KeyLength))
+		}
+
+		peer.allowedIPs = r.ipNets()
+		peers = append(peers, peer)
+	}
+
+	if r.err != nil {
+		return nil, nil, nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, nil, nil, fmt.Errorf("invalid node response result; %d unexpected trailing bytes", len(r.data))
+	}
+	if err := checkPeersAllowedIPs(peers); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return v4Addr, v6Addr, peers, nil
+}
+
+// checkPeersAllowedIPs fails when two peers would be routed the same
+// addresses, as WireGuard sends each packet to a single peer.
+func checkPeersAllowedIPs(peers []wireGuardPeer) error {
+	for i := range peers {
+		if len(peers[i].allowedIPs) == 0 {
+			return fmt.Errorf("invalid node response result; peer %d has no allowed IPs", i)
+		}
+
+		for j := i + 1; j < len(peers); j++ {
+			for _, a := range peers[i].allowedIPs {
+				for _, b := range peers[j].allowedIPs {
+					if a.Overlaps(b) {
+						return fmt.Errorf("invalid node response result; allowed IPs %s of peer %d overlap %s of peer %d",
+							a.String(), i, b.String(), j)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	gocontext "context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+const (
+	connectivityProbeURL     = "https://cp.cloudflare.com/generate_204"
+	connectivityProbeTimeout = 5 * time.Second
+	connectivityProbeTTL     = 30 * time.Second
+)
+
+// isInternetReachable tells whether a request through the tunnel gets any
+// response, as a handshake alone does not prove that routing and DNS work.
+// The result is kept for connectivityProbeTTL, so status polling does not
+// probe every time.
+func isInternetReachable(c gocontext.Context, ctx *context.Context, id uint64, service types.Service) bool {
+	if v, ok := ctx.Reachable(id, service); ok {
+		return v
+	}
+
+	reachable := probeConnectivity(c, service) == nil
+	if c.Err() == nil {
+		ctx.WithReachable(id, service, reachable, connectivityProbeTTL)
+	}
+
+	return reachable
+}
+
+func probeConnectivity(c gocontext.Context, service types.Service) error {
+	dial, err := newTunnelDialFunc(service)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: connectivityProbeTimeout,
+		Transport: &http.Transport{
+			DialContext:       dial,
+			DisableKeepAlives: true,
+		},
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodGet, connectivityProbeURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
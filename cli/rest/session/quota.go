@@ -0,0 +1,70 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+// StopSession brings the service down and forgets the session.
+func StopSession(ctx *context.Context, id uint64, service types.Service) error {
+	if err := utils.StopService(service); err != nil {
+		return err
+	}
+
+	path := types.StatusFilePath(ctx.Home(), id)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	ctx.WithService(id, nil)
+	ctx.NotifyWebhook(context.WebhookEventSessionStopped, id, service)
+	return nil
+}
+
+// watchQuota stops the session once it has transferred maxBytes in total,
+// counting across interface restarts.
+func watchQuota(ctx *context.Context, id uint64, service types.Service, maxBytes uint64) {
+	interval := time.Duration(ctx.Config().Bandwidth.Interval) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var counter types.BandwidthCounter
+		for range ticker.C {
+			if ctx.Service(id) != service {
+				return
+			}
+
+			download, upload, err := service.Transfer()
+			if err != nil {
+				continue
+			}
+
+			total := uint64(counter.Update(download + upload))
+			if total < maxBytes {
+				continue
+			}
+
+			reason := fmt.Sprintf("transferred %d bytes, reaching the local budget of %d bytes", total, maxBytes)
+			if wg, ok := service.(*wireguard.WireGuard); ok {
+				wg.Emit(wgt.NewEvent(wgt.EventQuotaExceeded).WithReason(reason))
+			}
+
+			log.Printf("Stopping session %d: %s", id, reason)
+			if err := StopSession(ctx, id, service); err != nil {
+				log.Printf("Failed to stop session %d: %s", id, err)
+			}
+
+			return
+		}
+	}()
+}
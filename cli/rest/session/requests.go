@@ -1,13 +1,39 @@
 package session
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
 type RequestAddSession struct {
-	To string `json:"to"`
+	To                  string   `json:"to"`
+	Type                string   `json:"type"`
+	Network             string   `json:"network"`
+	Routing             string   `json:"routing"`
+	DNS                 []string `json:"dns"`
+	PersistentKeepalive *uint64  `json:"persistent_keepalive"`
+	MTU                 uint64   `json:"mtu"`
+	ListenPort          *uint64  `json:"listen_port"`
+	Timeout             *uint64  `json:"timeout"`
+	Attempts            uint64   `json:"attempts"`
+	KillSwitch          bool     `json:"kill_switch"`
+	DNSGuard            bool     `json:"dns_guard"`
+	ExcludedIPs         []string `json:"excluded_ips"`
+	IncludedDomains     []string `json:"included_domains"`
+	DryRun              bool     `json:"dry_run"`
+	MaxBytes            *uint64  `json:"max_bytes"`
+	Hops                uint64   `json:"hops"`
+
+	CertificateFingerprint string `json:"certificate_fingerprint"`
+	Insecure               bool   `json:"insecure"`
 }
 
 func NewRequestAddSession(r *http.Request) (*RequestAddSession, error) {
@@ -23,6 +49,178 @@ func (r *RequestAddSession) Validate() error {
 	if r.To == "" {
 		return fmt.Errorf("invalid field To")
 	}
+	if r.Type != "" && r.Type != types.ServiceTypeWireGuard && r.Type != types.ServiceTypeV2Ray {
+		return fmt.Errorf("invalid field Type")
+	}
+	if r.KillSwitch && r.Type == types.ServiceTypeV2Ray {
+		return fmt.Errorf("invalid field KillSwitch; not supported for %s", r.Type)
+	}
+	if r.DNSGuard && r.Type == types.ServiceTypeV2Ray {
+		return fmt.Errorf("invalid field DNSGuard; not supported for %s", r.Type)
+	}
+	if r.Network != "" && r.Network != types.NetworkDual && r.Network != types.NetworkIPv4 && r.Network != types.NetworkIPv6 {
+		return fmt.Errorf("invalid field Network")
+	}
+	if r.Routing != "" && r.Routing != types.RoutingFull && r.Routing != types.RoutingNone && r.Routing != types.RoutingLANExempt {
+		return fmt.Errorf("invalid field Routing")
+	}
+	if r.Routing != "" && r.Routing != types.RoutingFull {
+		if r.Type == types.ServiceTypeV2Ray {
+			return fmt.Errorf("invalid field Routing; not supported for %s", r.Type)
+		}
+		if r.Hops > 1 {
+			return fmt.Errorf("invalid field Routing; not supported with Hops")
+		}
+		if r.DNSGuard && r.Routing == types.RoutingNone {
+			return fmt.Errorf("invalid field Routing; %s is not supported with DNSGuard", r.Routing)
+		}
+		if len(r.ExcludedIPs) > 0 || len(r.IncludedDomains) > 0 {
+			return fmt.Errorf("invalid field Routing; not supported with ExcludedIPs or IncludedDomains")
+		}
+	}
+	for _, dns := range r.DNS {
+		ip := net.ParseIP(dns)
+		if ip == nil {
+			return fmt.Errorf("invalid field DNS")
+		}
+		if r.Network == types.NetworkIPv4 && ip.To4() == nil {
+			return fmt.Errorf("invalid field DNS; %s is not an IPv4 address", dns)
+		}
+		if r.Network == types.NetworkIPv6 && ip.To4() != nil {
+			return fmt.Errorf("invalid field DNS; %s is not an IPv6 address", dns)
+		}
+	}
+	if r.DNSGuard && r.Network == types.NetworkIPv6 && len(r.DNS) == 0 {
+		return fmt.Errorf("invalid field DNS; expected IPv6 resolvers with DNSGuard and Network %s", r.Network)
+	}
+	if r.PersistentKeepalive != nil && *r.PersistentKeepalive > math.MaxUint16 {
+		return fmt.Errorf("invalid field PersistentKeepalive")
+	}
+	if r.MTU != 0 && (r.MTU < 576 || r.MTU > 1500) {
+		return fmt.Errorf("invalid field MTU")
+	}
+	if r.ListenPort != nil {
+		if r.Type == types.ServiceTypeV2Ray {
+			return fmt.Errorf("invalid field ListenPort; not supported for %s", r.Type)
+		}
+		if *r.ListenPort < 1 || *r.ListenPort > math.MaxUint16 {
+			return fmt.Errorf("invalid field ListenPort; expected value in range 1-65535")
+		}
+	}
+	for _, cidr := range r.ExcludedIPs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid field ExcludedIPs")
+		}
+	}
+	for _, domain := range r.IncludedDomains {
+		if domain == "" {
+			return fmt.Errorf("invalid field IncludedDomains")
+		}
+	}
+	if len(r.ExcludedIPs) > 0 && len(r.IncludedDomains) > 0 {
+		return fmt.Errorf("invalid fields ExcludedIPs and IncludedDomains; expected only one of them")
+	}
+	if r.Hops > 1 {
+		if r.Type == types.ServiceTypeV2Ray {
+			return fmt.Errorf("invalid field Hops; not supported for %s", r.Type)
+		}
+		if r.Hops > maxHops {
+			return fmt.Errorf("invalid field Hops; expected value in range 1-%d", maxHops)
+		}
+		if len(r.ExcludedIPs) > 0 || len(r.IncludedDomains) > 0 {
+			return fmt.Errorf("invalid field Hops; not supported with ExcludedIPs or IncludedDomains")
+		}
+	}
+	if r.MaxBytes != nil && *r.MaxBytes == 0 {
+		return fmt.Errorf("invalid field MaxBytes; expected positive value")
+	}
+	if r.Attempts > 10 {
+		return fmt.Errorf("invalid field Attempts")
+	}
+	if r.CertificateFingerprint != "" {
+		fingerprint, err := hex.DecodeString(r.CertificateFingerprint)
+		if err != nil || len(fingerprint) != sha256.Size {
+			return fmt.Errorf("invalid field CertificateFingerprint")
+		}
+	}
+
+	return nil
+}
+
+// validateRemoteURL checks a node remote URL before the session path is
+// appended to it.
+func validateRemoteURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil {
+		return fmt.Errorf("invalid node remote URL %q; %s", v, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("invalid node remote URL %q; expected https scheme", v)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid node remote URL %q; expected non-empty host", v)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid node remote URL %q; expected no path, query or fragment", v)
+	}
+
+	return nil
+}
+
+type RequestCancelConnect struct {
+	ID uint64 `json:"id"`
+}
+
+func NewRequestCancelConnect(r *http.Request) (*RequestCancelConnect, error) {
+	var body RequestCancelConnect
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestCancelConnect) Validate() error {
+	if r.ID == 0 {
+		return fmt.Errorf("invalid field ID")
+	}
+
+	return nil
+}
+
+type RequestSpeedTest struct {
+	DownloadURL string `json:"download_url"`
+	UploadURL   string `json:"upload_url"`
+	Size        uint64 `json:"size"`
+	Duration    uint64 `json:"duration"`
+}
+
+func NewRequestSpeedTest(r *http.Request) (*RequestSpeedTest, error) {
+	var body RequestSpeedTest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+func (r *RequestSpeedTest) Validate() error {
+	if r.DownloadURL != "" {
+		if _, err := url.ParseRequestURI(r.DownloadURL); err != nil {
+			return fmt.Errorf("invalid field DownloadURL")
+		}
+	}
+	if r.UploadURL != "" {
+		if _, err := url.ParseRequestURI(r.UploadURL); err != nil {
+			return fmt.Errorf("invalid field UploadURL")
+		}
+	}
+	if r.Size > maxSpeedTestSize {
+		return fmt.Errorf("invalid field Size")
+	}
+	if r.Duration > maxSpeedTestDuration {
+		return fmt.Errorf("invalid field Duration")
+	}
 
 	return nil
 }
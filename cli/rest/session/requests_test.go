@@ -0,0 +1,101 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func TestRequestAddSessionValidateDNS(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		dns      []string
+		dnsGuard bool
+		err      bool
+	}{
+		{"dual with mixed resolvers", types.NetworkDual, []string{"10.8.0.1", "fd86:ea04:1115::1"}, false, false},
+		{"default network with IPv6 resolver", "", []string{"2606:4700:4700::1111"}, false, false},
+		{"IPv4 with IPv4 resolver", types.NetworkIPv4, []string{"1.1.1.1"}, false, false},
+		{"IPv4 with IPv6 resolver", types.NetworkIPv4, []string{"1.1.1.1", "2606:4700:4700::1111"}, false, true},
+		{"IPv6 with IPv6 resolver", types.NetworkIPv6, []string{"2606:4700:4700::1111"}, false, false},
+		{"IPv6 with IPv4 resolver", types.NetworkIPv6, []string{"1.1.1.1"}, false, true},
+		{"IPv6 with IPv4-mapped resolver", types.NetworkIPv6, []string{"::ffff:1.1.1.1"}, false, true},
+		{"IPv6 with DNS guard and resolver", types.NetworkIPv6, []string{"2606:4700:4700::1111"}, true, false},
+		{"IPv6 with DNS guard without resolver", types.NetworkIPv6, nil, true, true},
+		{"not an IP", "", []string{"resolver.example"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &RequestAddSession{
+				To:       "node",
+				Network:  tt.network,
+				DNS:      tt.dns,
+				DNSGuard: tt.dnsGuard,
+			}
+
+			if err := body.Validate(); (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateRemoteURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		err  bool
+	}{
+		{"host", "https://node.example", false},
+		{"host and port", "https://node.example:8585", false},
+		{"trailing slash", "https://node.example:8585/", false},
+		{"IPv4 host", "https://203.0.113.1:8585", false},
+		{"IPv6 host", "https://[2001:db8::1]:8585", false},
+		{"empty", "", true},
+		{"http scheme", "http://node.example:8585", true},
+		{"no scheme", "node.example:8585", true},
+		{"no host", "https://", true},
+		{"path", "https://node.example:8585/api", true},
+		{"query", "https://node.example:8585?a=b", true},
+		{"fragment", "https://node.example:8585#a", true},
+		{"malformed", "https://node.example:port", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRemoteURL(tt.url); (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRequestAddSessionValidateExcludedIPs(t *testing.T) {
+	tests := []struct {
+		name        string
+		excludedIPs []string
+		err         bool
+	}{
+		{"none", nil, false},
+		{"IPv4 and IPv6", []string{"192.168.0.0/16", "fd00::/8"}, false},
+		{"host address", []string{"192.168.1.1/32"}, false},
+		{"without a prefix length", []string{"192.168.0.0"}, true},
+		{"invalid prefix length", []string{"192.168.0.0/33"}, true},
+		{"not an IP", []string{"lan/16"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &RequestAddSession{
+				To:          "node",
+				ExcludedIPs: tt.excludedIPs,
+			}
+
+			if err := body.Validate(); (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
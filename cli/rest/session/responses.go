@@ -0,0 +1,104 @@
+package session
+
+import (
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/x/session"
+)
+
+type ResponseSessionStatus struct {
+	Upload            int64     `json:"upload"`
+	Download          int64     `json:"download"`
+	LatestHandshake   time.Time `json:"latest_handshake"`
+	Connected         bool      `json:"connected"`
+	InternetReachable bool      `json:"internet_reachable"`
+}
+
+type ResponseSessions struct {
+	Sessions session.Sessions `json:"sessions"`
+	Offset   uint64           `json:"offset"`
+	Limit    uint64           `json:"limit"`
+	Total    uint64           `json:"total"`
+	HasMore  bool             `json:"has_more"`
+}
+
+// newResponseSessions returns the page of the sorted items at the offset.
+func newResponseSessions(items session.Sessions, offset, limit uint64) ResponseSessions {
+	var (
+		total = uint64(len(items))
+	)
+
+	return ResponseSessions{
+		Sessions: items.Page(offset, limit),
+		Offset:   offset,
+		Limit:    limit,
+		Total:    total,
+		HasMore:  offset < total && limit < total-offset,
+	}
+}
+
+type ResponseActiveSession struct {
+	ID        uint64 `json:"id"`
+	Node      string `json:"node"`
+	RemoteURL string `json:"remote_url"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+}
+
+type ResponseDNSLeak struct {
+	Resolvers []string `json:"resolvers"`
+	TunnelDNS []string `json:"tunnel_dns"`
+}
+
+type ResponseCancelConnect struct {
+	ID      uint64 `json:"id"`
+	Aborted bool   `json:"aborted"`
+}
+
+type ResponseResolve struct {
+	Name      string   `json:"name"`
+	Resolver  string   `json:"resolver"`
+	Servers   []string `json:"servers"`
+	Addresses []string `json:"addresses"`
+}
+
+type ResponseSpeedTest struct {
+	DownloadMbps float64 `json:"download_mbps"`
+	UploadMbps   float64 `json:"upload_mbps"`
+	RTT          float64 `json:"rtt_ms"`
+}
+
+type ResponseEndpoint struct {
+	Host string `json:"host"`
+	Port uint16 `json:"port"`
+}
+
+type ResponseStartSession struct {
+	ID         uint64           `json:"id"`
+	Type       string           `json:"type"`
+	Interface  string           `json:"interface"`
+	Addresses  []string         `json:"addresses,omitempty"`
+	Endpoint   ResponseEndpoint `json:"endpoint"`
+	ListenPort uint16           `json:"listen_port"`
+	DryRun     bool             `json:"dry_run,omitempty"`
+	Config     string           `json:"config,omitempty"`
+}
+
+type ResponseSessionConfig struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Config   string `json:"config"`
+	Redacted bool   `json:"redacted"`
+}
+
+type ResponseRotateKey struct {
+	ResponseStartSession
+	AddressesChanged bool `json:"addresses_changed"`
+}
+
+type ResponseSessionBandwidth struct {
+	Active   bool                    `json:"active"`
+	Interval uint64                  `json:"interval"`
+	Samples  []types.BandwidthSample `json:"samples"`
+}
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/x/session"
+)
+
+func TestNewResponseSessions(t *testing.T) {
+	var items session.Sessions
+	for id := uint64(1); id <= 5; id++ {
+		items = append(items, session.Session{Id: id})
+	}
+	items.Sort("-id")
+
+	tests := []struct {
+		name    string
+		offset  uint64
+		limit   uint64
+		ids     []uint64
+		hasMore bool
+	}{
+		{"first page", 0, 2, []uint64{5, 4}, true},
+		{"middle page", 2, 2, []uint64{3, 2}, true},
+		{"last page", 4, 2, []uint64{1}, false},
+		{"exact end", 3, 2, []uint64{2, 1}, false},
+		{"past the end", 10, 2, nil, false},
+		{"everything", 0, 100, []uint64{5, 4, 3, 2, 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := newResponseSessions(items, tt.offset, tt.limit)
+			if res.Total != 5 {
+				t.Fatalf("total %d, want 5", res.Total)
+			}
+			if res.HasMore != tt.hasMore {
+				t.Fatalf("has more %t, want %t", res.HasMore, tt.hasMore)
+			}
+			if len(res.Sessions) != len(tt.ids) {
+				t.Fatalf("got %d sessions, want %d", len(res.Sessions), len(tt.ids))
+			}
+			for i, id := range tt.ids {
+				if res.Sessions[i].Id != id {
+					t.Fatalf("session %d has id %d, want %d", i, res.Sessions[i].Id, id)
+				}
+			}
+		})
+	}
+}
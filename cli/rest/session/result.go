@@ -0,0 +1,186 @@
+package session
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+const (
+	// NodeResultVersionLegacy is the version of results without a marker.
+	NodeResultVersionLegacy = 0
+	NodeResultVersion1      = 1
+	NodeResultVersion2      = 2
+)
+
+// nodeResultMarker starts every versioned result and is followed by the
+// version byte. Legacy results start with the IPv4 address of the peer, which
+// is never in 255.0.0.0/8, so no legacy result starts with the marker.
+var nodeResultMarker = []byte{0xff, 'S', 'N'}
+
+// NodeConnInfo is the connection a node returns for a single peer WireGuard
+// session. AllowedIPs are the networks the node routes, empty for a full
+// tunnel.
+type NodeConnInfo struct {
+	Version      uint8
+	IPv4Addr     net.IP
+	IPv6Addr     net.IP
+	Host         net.IP
+	Port         uint16
+	PublicKey    wgt.Key
+	PresharedKey *wgt.Key
+	AllowedIPs   []wgt.IPNet
+}
+
+func isLegacyNodeResultLength(n int) bool {
+	switch n {
+	case wireGuardResultLengthIPv4, wireGuardResultLengthIPv6,
+		wireGuardResultLengthIPv4 + wgt.KeyLength, wireGuardResultLengthIPv6 + wgt.KeyLength:
+		return true
+	default:
+		return false
+	}
+}
+
+func isVersionedNodeResult(data []byte) bool {
+	return len(data) > len(nodeResultMarker) && bytes.HasPrefix(data, nodeResultMarker)
+}
+
+// ParseNodeAddSessionResponse parses the result of a single peer WireGuard
+// session. Legacy results and version 1 are laid out as
+//
+//	[marker (3) | version (1)] | IPv4 address (4) | IPv6 address (16) |
+//	host (4 or 16) | port (2) | public key (32) | [preshared key (32)]
+//
+// and version 2, which may name the networks the node routes, as
+//
+//	marker (3) | version (1) | IPv4 address (4) | IPv6 address (16) |
+//	host length (1) | host (4 or 16) | port (2) | public key (32) |
+//	has preshared key (1) | [preshared key (32)] | allowed IP count (1) |
+//	allowed IPs, each as IP length (1) | IP (4 or 16) | prefix length (1)
+//
+// The marker is checked first, so that a result is never told apart by its
+// length and a newer node format is rejected rather than misread.
+func ParseNodeAddSessionResponse(data []byte) (*NodeConnInfo, error) {
+	var (
+		info *NodeConnInfo
+		err  error
+	)
+
+	if isVersionedNodeResult(data) {
+		version, body := data[len(nodeResultMarker)], data[len(nodeResultMarker)+1:]
+		switch version {
+		case NodeResultVersion1:
+			info, err = parseNodeResultV1(body)
+		case NodeResultVersion2:
+			info, err = parseNodeResultV2(body)
+		default:
+			return nil, fmt.Errorf("invalid node response result; unsupported version %d", version)
+		}
+		if err == nil {
+			info.Version = version
+		}
+	} else {
+		info, err = parseNodeResultV1(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Host.IsUnspecified() {
+		return nil, fmt.Errorf("invalid node response result; unspecified host %s", info.Host)
+	}
+	if info.Port == 0 {
+		return nil, fmt.Errorf("invalid node response result; invalid port 0")
+	}
+	if info.PublicKey.IsZero() {
+		return nil, fmt.Errorf("invalid node response result; empty public key")
+	}
+
+	return info, nil
+}
+
+func parseNodeResultV1(data []byte) (*NodeConnInfo, error) {
+	if !isLegacyNodeResultLength(len(data)) {
+		return nil, fmt.Errorf("invalid node response result length %d; expected %d or %d, optionally followed by a %d byte preshared key",
+			len(data), wireGuardResultLengthIPv4, wireGuardResultLengthIPv6, wgt.KeyLength)
+	}
+
+	hostLength := net.IPv4len
+	if n := len(data); n == wireGuardResultLengthIPv6 || n == wireGuardResultLengthIPv6+wgt.KeyLength {
+		hostLength = net.IPv6len
+	}
+
+	r := &resultReader{data: data}
+	info := &NodeConnInfo{
+		IPv4Addr:  net.IP(r.next(net.IPv4len)),
+		IPv6Addr:  net.IP(r.next(net.IPv6len)),
+		Host:      net.IP(r.next(hostLength)),
+		Port:      binary.BigEndian.Uint16(r.next(2)),
+		PublicKey: *wgt.NewKey(r.next(wgt.KeyLength)),
+	}
+	if len(r.data) == wgt.KeyLength {
+		info.PresharedKey = wgt.NewKey(r.next(wgt.KeyLength))
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return info, nil
+}
+
+func parseNodeResultV2(data []byte) (*NodeConnInfo, error) {
+	r := &resultReader{data: data}
+	info := &NodeConnInfo{
+		IPv4Addr:  net.IP(r.next(net.IPv4len)),
+		IPv6Addr:  net.IP(r.next(net.IPv6len)),
+		Host:      r.ip(),
+		Port:      binary.BigEndian.Uint16(r.next(2)),
+		PublicKey: *wgt.NewKey(r.next(wgt.KeyLength)),
+	}
+	if r.next(1)[0] != 0 {
+		info.PresharedKey = wgt.NewKey(r.next(wgt.KeyLength))
+	}
+
+	info.AllowedIPs = r.ipNets()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, fmt.Errorf("invalid node response result; %d unexpected trailing bytes", len(r.data))
+	}
+
+	return info, nil
+}
+
+// nodeResultKeysOffset returns where the keys of a single peer result start,
+// or the length of the result if it is too short to tell.
+func nodeResultKeysOffset(data []byte) int {
+	n, offset := len(data), 0
+	versioned := isVersionedNodeResult(data)
+	if versioned {
+		offset = len(nodeResultMarker) + 1
+		n -= offset
+	}
+
+	hostLength := net.IPv4len
+	switch {
+	case versioned && data[offset-1] == NodeResultVersion2:
+		if n <= net.IPv4len+net.IPv6len {
+			return len(data)
+		}
+
+		hostLength, offset = int(data[offset+net.IPv4len+net.IPv6len]), offset+1
+	case n == wireGuardResultLengthIPv6 || n == wireGuardResultLengthIPv6+wgt.KeyLength:
+		hostLength = net.IPv6len
+	}
+
+	if keep := offset + net.IPv4len + net.IPv6len + hostLength + 2; keep < len(data) {
+		return keep
+	}
+
+	return len(data)
+}
@@ -0,0 +1,178 @@
+package session
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+type testNodeResult struct {
+	version      uint8
+	v4Addr       net.IP
+	host         net.IP
+	presharedKey bool
+	allowedIPs   []wgt.IPNet
+}
+
+func (r testNodeResult) bytes() []byte {
+	var buf bytes.Buffer
+	if r.version != NodeResultVersionLegacy {
+		buf.Write(nodeResultMarker)
+		buf.WriteByte(r.version)
+	}
+
+	v4Addr := r.v4Addr
+	if v4Addr == nil {
+		v4Addr = net.ParseIP("10.8.0.2")
+	}
+
+	buf.Write(v4Addr.To4())
+	buf.Write(net.ParseIP("fd86:ea04:1115::2").To16())
+
+	host := r.host
+	if v := host.To4(); v != nil {
+		host = v
+	}
+	if r.version == NodeResultVersion2 {
+		buf.WriteByte(byte(len(host)))
+	}
+	buf.Write(host)
+
+	port := make([]byte, 2)
+	binary.BigEndian.PutUint16(port, 51820)
+	buf.Write(port)
+	buf.Write(bytes.Repeat([]byte{1}, wgt.KeyLength))
+
+	if r.version == NodeResultVersion2 {
+		if r.presharedKey {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	}
+	if r.presharedKey {
+		buf.Write(bytes.Repeat([]byte{2}, wgt.KeyLength))
+	}
+
+	if r.version == NodeResultVersion2 {
+		buf.WriteByte(byte(len(r.allowedIPs)))
+		for _, item := range r.allowedIPs {
+			buf.WriteByte(byte(len(item.IP)))
+			buf.Write(item.IP)
+			buf.WriteByte(item.Net)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func TestParseNodeAddSessionResponse(t *testing.T) {
+	exitRange := wgt.IPNet{IP: net.ParseIP("192.0.2.0").To4(), Net: 24}
+	tests := []struct {
+		name   string
+		result testNodeResult
+	}{
+		{"legacy IPv4 host", testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("203.0.113.1")}},
+		{"legacy IPv6 host", testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("2001:db8::1")}},
+		{"legacy with preshared key", testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("203.0.113.1"), presharedKey: true}},
+		{"v1 IPv4 host", testNodeResult{version: NodeResultVersion1, host: net.ParseIP("203.0.113.1")}},
+		{"v1 IPv6 host with preshared key", testNodeResult{version: NodeResultVersion1, host: net.ParseIP("2001:db8::1"), presharedKey: true}},
+		{"v2 full tunnel", testNodeResult{version: NodeResultVersion2, host: net.ParseIP("203.0.113.1")}},
+		{"v2 allowed IPs", testNodeResult{version: NodeResultVersion2, host: net.ParseIP("2001:db8::1"), presharedKey: true,
+			allowedIPs: []wgt.IPNet{exitRange}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseNodeAddSessionResponse(tt.result.bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Version != tt.result.version {
+				t.Errorf("expected version %d, got %d", tt.result.version, info.Version)
+			}
+			if !info.IPv4Addr.Equal(net.ParseIP("10.8.0.2")) || !info.IPv6Addr.Equal(net.ParseIP("fd86:ea04:1115::2")) {
+				t.Errorf("unexpected addresses %s and %s", info.IPv4Addr, info.IPv6Addr)
+			}
+			if !info.Host.Equal(tt.result.host) {
+				t.Errorf("expected host %s, got %s", tt.result.host, info.Host)
+			}
+			if info.Port != 51820 {
+				t.Errorf("expected port 51820, got %d", info.Port)
+			}
+			if (info.PresharedKey != nil) != tt.result.presharedKey {
+				t.Errorf("expected preshared key %t, got %t", tt.result.presharedKey, info.PresharedKey != nil)
+			}
+			if len(info.AllowedIPs) != len(tt.result.allowedIPs) {
+				t.Fatalf("expected %d allowed IPs, got %d", len(tt.result.allowedIPs), len(info.AllowedIPs))
+			}
+			for i := range info.AllowedIPs {
+				if info.AllowedIPs[i].String() != tt.result.allowedIPs[i].String() {
+					t.Errorf("expected allowed IP %s, got %s", tt.result.allowedIPs[i].String(), info.AllowedIPs[i].String())
+				}
+			}
+		})
+	}
+}
+
+func TestParseNodeAddSessionResponseInvalid(t *testing.T) {
+	v1 := testNodeResult{version: NodeResultVersion1, host: net.ParseIP("203.0.113.1")}.bytes()
+	v2 := testNodeResult{version: NodeResultVersion2, host: net.ParseIP("203.0.113.1"), presharedKey: true,
+		allowedIPs: []wgt.IPNet{{IP: net.ParseIP("192.0.2.0").To4(), Net: 24}}}.bytes()
+	legacy := testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("203.0.113.1")}.bytes()
+
+	futureVersion := append([]byte(nil), v1...)
+	futureVersion[len(nodeResultMarker)] = 3
+
+	badPrefix := append([]byte(nil), v2...)
+	badPrefix[len(badPrefix)-1] = 33
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"marker only", nodeResultMarker},
+		{"legacy truncated", legacy[:len(legacy)-1]},
+		{"legacy trailing byte", append(append([]byte(nil), legacy...), 0)},
+		{"v1 truncated", v1[:len(v1)-1]},
+		{"v2 truncated in keys", v2[:len(nodeResultMarker)+1+net.IPv4len+net.IPv6len+1+net.IPv4len+2+10]},
+		{"v2 truncated in allowed IPs", v2[:len(v2)-1]},
+		{"v2 trailing byte", append(append([]byte(nil), v2...), 0)},
+		{"v2 invalid prefix length", badPrefix},
+		{"future version", futureVersion},
+		{"unspecified host", testNodeResult{version: NodeResultVersion1, host: net.IPv4zero}.bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseNodeAddSessionResponse(tt.data); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestNodeResultKeysOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		result testNodeResult
+		offset int
+	}{
+		{"legacy IPv4 host", testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("203.0.113.1")}, 26},
+		{"legacy IPv6 host", testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("2001:db8::1")}, 38},
+		{"v1 IPv4 host", testNodeResult{version: NodeResultVersion1, host: net.ParseIP("203.0.113.1")}, 30},
+		{"v2 IPv6 host", testNodeResult{version: NodeResultVersion2, host: net.ParseIP("2001:db8::1")}, 43},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if offset := nodeResultKeysOffset(tt.result.bytes()); offset != tt.offset {
+				t.Fatalf("expected offset %d, got %d", tt.offset, offset)
+			}
+		})
+	}
+}
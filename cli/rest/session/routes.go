@@ -10,12 +10,51 @@ import (
 
 func RegisterRoutes(r *mux.Router, ctx *context.Context) {
 	r.Name("GetSession").
-		Methods(http.MethodGet).Path("/sessions/{id}").
+		Methods(http.MethodGet, http.MethodHead).Path("/sessions/{id}").
 		HandlerFunc(HandlerGetSession(ctx))
+	r.Name("GetActiveSessions").
+		Methods(http.MethodGet, http.MethodHead).Path("/session/active").
+		HandlerFunc(HandlerGetActiveSessions(ctx))
+	r.Name("CheckDNSLeak").
+		Methods(http.MethodGet, http.MethodHead).Path("/session/dns-leak").
+		HandlerFunc(HandlerCheckDNSLeak(ctx))
+	r.Name("CancelConnect").
+		Methods(http.MethodPost).Path("/session/cancel").
+		HandlerFunc(HandlerCancelConnect(ctx))
+	r.Name("Reconnect").
+		Methods(http.MethodPost).Path("/session/reconnect").
+		HandlerFunc(HandlerReconnect(ctx))
+	r.Name("Resolve").
+		Methods(http.MethodGet, http.MethodHead).Path("/session/resolve").
+		HandlerFunc(HandlerResolve(ctx))
 	r.Name("GetSessionsForAddress").
-		Methods(http.MethodGet).Path("/accounts/{address}/sessions").
+		Methods(http.MethodGet, http.MethodHead).Path("/accounts/{address}/sessions").
 		HandlerFunc(HandlerGetSessionsForAddress(ctx))
+	r.Name("GetSessionsForNode").
+		Methods(http.MethodGet, http.MethodHead).Path("/nodes/{address}/sessions").
+		HandlerFunc(HandlerGetSessionsForNode(ctx))
+	r.Name("GetSessionStatus").
+		Methods(http.MethodGet, http.MethodHead).Path("/sessions/{id}/status").
+		HandlerFunc(HandlerGetSessionStatus(ctx))
+	r.Name("GetSessionBandwidth").
+		Methods(http.MethodGet, http.MethodHead).Path("/sessions/{id}/bandwidth").
+		HandlerFunc(HandlerGetSessionBandwidth(ctx))
+	r.Name("GetSessionConfig").
+		Methods(http.MethodGet, http.MethodHead).Path("/sessions/{id}/config").
+		HandlerFunc(HandlerGetSessionConfig(ctx))
+	r.Name("GetSessionEvents").
+		Methods(http.MethodGet).Path("/sessions/{id}/events").
+		HandlerFunc(HandlerGetSessionEvents(ctx))
+	r.Name("RotateKey").
+		Methods(http.MethodPost).Path("/sessions/{id}/rekey").
+		HandlerFunc(HandlerRotateKey(ctx))
+	r.Name("SpeedTest").
+		Methods(http.MethodPost).Path("/sessions/{id}/speedtest").
+		HandlerFunc(HandlerSpeedTest(ctx))
 	r.Name("StartSession").
 		Methods(http.MethodPost).Path("/accounts/{address}/subscriptions/{id}/sessions").
 		HandlerFunc(HandlerStartSession(ctx))
+	r.Name("StopSession").
+		Methods(http.MethodDelete).Path("/sessions/{id}").
+		HandlerFunc(HandlerStopSession(ctx))
 }
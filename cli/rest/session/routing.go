@@ -0,0 +1,66 @@
+package session
+
+import (
+	"net"
+
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+var (
+	// lanExemptCIDRs are kept off the tunnel by the lan-exempt routing preset,
+	// along with the networks of the local interfaces.
+	lanExemptCIDRs = []string{
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"169.254.0.0/16",
+		"fe80::/10",
+	}
+)
+
+// lanNetworks returns the private and link-local ranges, and the networks
+// the up interfaces are attached to. Loopback and point-to-point interfaces,
+// which include the tunnels, are skipped.
+func lanNetworks() ([]wgt.IPNet, error) {
+	items := make([]wgt.IPNet, 0, len(lanExemptCIDRs))
+	for _, cidr := range lanExemptCIDRs {
+		ipNet, err := wgt.NewIPNetFromCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, *ipNet)
+	}
+
+	iFaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iFace := range iFaces {
+		if iFace.Flags&net.FlagUp == 0 || iFace.Flags&(net.FlagLoopback|net.FlagPointToPoint) != 0 {
+			continue
+		}
+
+		addrs, err := iFace.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+
+			ones, bits := ipNet.Mask.Size()
+			if ones == 0 || ones == bits {
+				continue
+			}
+
+			items = append(items, wgt.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Net: uint8(ones)})
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,561 @@
+package session
+
+import (
+	gocontext "context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/services/v2ray"
+	v2raytypes "github.com/sentinel-official/desktop-client/cli/services/v2ray/types"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+// Legacy WireGuard results carry the peer host as 4 bytes for IPv4 or 16
+// bytes for IPv6, optionally followed by a 32 byte preshared key, which is
+// told apart by the result length. See ParseNodeAddSessionResponse for the
+// layouts.
+const (
+	wireGuardResultLengthIPv4 = 58
+	wireGuardResultLengthIPv6 = 70
+)
+
+// checkResult checks a result is whole before it is decoded into a service.
+func checkResult(t string, hops uint64, result []byte) error {
+	n := len(result)
+	if t == types.ServiceTypeV2Ray {
+		if n != 7 {
+			return fmt.Errorf("invalid node response result length %d; expected 7", n)
+		}
+
+		return nil
+	}
+	if hops > 1 {
+		_, _, _, err := decodeMultiHopResult(result, hops)
+		return err
+	}
+
+	_, err := ParseNodeAddSessionResponse(result)
+	return err
+}
+
+// redactResult returns a copy of a node result with its keys zeroed, keeping
+// the addresses and endpoint that help telling why a result was rejected. A
+// malformed result is kept only up to where its keys would start.
+func redactResult(t string, hops uint64, result []byte) []byte {
+	if t == types.ServiceTypeV2Ray {
+		return result
+	}
+
+	keep := net.IPv4len + net.IPv6len + 1
+	if hops <= 1 {
+		keep = nodeResultKeysOffset(result)
+	}
+
+	redacted := make([]byte, len(result))
+	if keep > len(result) {
+		keep = len(result)
+	}
+
+	copy(redacted, result[:keep])
+	return redacted
+}
+
+// logNodeResult logs the session result a node returned, with its keys
+// redacted, when logging of node responses is enabled.
+func logNodeResult(ctx *context.Context, address string, body *RequestAddSession, data string, result []byte, err error) {
+	if !ctx.Config().Log.NodeResponses {
+		return
+	}
+	if err != nil {
+		log.Printf("Node %s returned a session result of %d characters that is not valid base64: %s", address, len(data), err)
+		return
+	}
+
+	log.Printf("Node %s returned a session result of %d bytes: %s (keys redacted)",
+		address, len(result), base64.StdEncoding.EncodeToString(redactResult(body.Type, body.Hops, result)))
+}
+
+// newSessionRequest returns the body of a session request to the node, which
+// asks for more than one hop only when the request does.
+func newSessionRequest(key string, hops uint64) ([]byte, error) {
+	request := map[string]interface{}{
+		"key": key,
+	}
+	if hops > 1 {
+		request["hops"] = hops
+	}
+
+	return json.Marshal(request)
+}
+
+func filterIPNets(items []wgt.IPNet, network string) []wgt.IPNet {
+	filtered := make([]wgt.IPNet, 0, len(items))
+	for i := range items {
+		if items[i].IsIPv4() == (network == types.NetworkIPv4) {
+			filtered = append(filtered, items[i])
+		}
+	}
+
+	return filtered
+}
+
+func checkExclusiveFeatures(ctx *context.Context, body *RequestAddSession) error {
+	ids := ctx.ServiceIDs()
+	if len(ids) == 0 {
+		return nil
+	}
+	if body.KillSwitch || body.DNSGuard {
+		return fmt.Errorf("kill switch and DNS guard require no other active sessions")
+	}
+
+	for _, id := range ids {
+		service := ctx.Service(id)
+		if service == nil {
+			continue
+		}
+
+		var status types.Status
+		if err := json.Unmarshal(service.Info(), &status); err != nil {
+			return err
+		}
+		if status.KillSwitch || status.DNSGuard {
+			return fmt.Errorf("session %d is using the kill switch or DNS guard", id)
+		}
+	}
+
+	return nil
+}
+
+func newWireGuardService(ctx *context.Context, body *RequestAddSession, status *types.Status, privateKey *wgt.Key, result []byte) (types.Service, error) {
+	if body.Hops > 1 {
+		v4Addr, v6Addr, peers, err := decodeMultiHopResult(result, body.Hops)
+		if err != nil {
+			return nil, err
+		}
+
+		return buildWireGuardService(ctx, body, status, privateKey, v4Addr, v6Addr, peers)
+	}
+
+	info, err := ParseNodeAddSessionResponse(result)
+	if err != nil {
+		return nil, err
+	}
+
+	peer := wireGuardPeer{
+		host:       info.Host,
+		port:       info.Port,
+		publicKey:  info.PublicKey,
+		allowedIPs: info.AllowedIPs,
+	}
+	if info.PresharedKey != nil {
+		peer.presharedKey = *info.PresharedKey
+	}
+
+	return buildWireGuardService(ctx, body, status, privateKey, info.IPv4Addr, info.IPv6Addr, []wireGuardPeer{peer})
+}
+
+// defaultDNS returns the resolver the node runs on the first address of its
+// IPv4 tunnel network. An IPv6 only tunnel does not route that address, and
+// the node advertises no IPv6 resolver, so it gets none and keeps the DNS of
+// the system unless the request names the resolvers.
+func defaultDNS(network string, v4Addr net.IP) []net.IP {
+	if network == types.NetworkIPv6 {
+		return nil
+	}
+
+	return []net.IP{
+		net.IPv4(v4Addr[0], v4Addr[1], v4Addr[2], 1),
+	}
+}
+
+// buildWireGuardService builds the service for the decoded result. A single
+// peer is routed what the request asks for out of the networks the node
+// routes, while the peers of a multi-hop session come with their own allowed
+// IPs.
+func buildWireGuardService(ctx *context.Context, body *RequestAddSession, status *types.Status, privateKey *wgt.Key,
+	v4Addr, v6Addr net.IP, peers []wireGuardPeer) (types.Service, error) {
+	name, err := wgt.InterfaceName(ctx.Config().WireGuard.InterfacePrefix, status.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var listenPort uint16
+	if body.ListenPort != nil {
+		listenPort = uint16(*body.ListenPort)
+	} else {
+		listenPort, err = utils.GetFreeUDPPort()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dns := defaultDNS(body.Network, v4Addr)
+	if len(body.DNS) > 0 {
+		dns = make([]net.IP, 0, len(body.DNS))
+		for _, item := range body.DNS {
+			dns = append(dns, net.ParseIP(item))
+		}
+	} else if body.Routing == types.RoutingNone {
+		// Nothing is routed through the tunnel, so neither is the node's
+		// resolver.
+		dns = nil
+	}
+
+	if len(peers) == 1 {
+		peers[0].allowedIPs, err = newAllowedIPs(ctx, body, peers[0].allowedIPs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	peers = routePeers(peers)
+
+	addresses := []wgt.IPNet{
+		{IP: v4Addr, Net: 32},
+		{IP: v6Addr, Net: 128},
+	}
+	if body.Network == types.NetworkIPv4 || body.Network == types.NetworkIPv6 {
+		addresses = filterIPNets(addresses, body.Network)
+		for i := range peers {
+			peers[i].allowedIPs = filterIPNets(peers[i].allowedIPs, body.Network)
+		}
+	}
+
+	keepalive := uint16(15)
+	if body.PersistentKeepalive != nil {
+		keepalive = uint16(*body.PersistentKeepalive)
+	}
+
+	cfg := &wgt.Config{
+		Name: name,
+		Interface: wgt.Interface{
+			Addresses:  addresses,
+			ListenPort: listenPort,
+			MTU:        uint16(body.MTU),
+			PrivateKey: *privateKey,
+			DNS:        dns,
+		},
+	}
+
+	for i := range peers {
+		cfg.Peers = append(cfg.Peers,
+			wgt.Peer{
+				PublicKey:    peers[i].publicKey,
+				PresharedKey: peers[i].presharedKey,
+				AllowedIPs:   peers[i].allowedIPs,
+				Endpoint: wgt.Endpoint{
+					Host: peers[i].host.String(),
+					Port: peers[i].port,
+				},
+				PersistentKeepalive: keepalive,
+			},
+		)
+	}
+
+	info, err := json.Marshal(status.WithName(cfg.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return wireguard.NewWireGuard().
+		WithConfig(cfg).
+		WithConfigDir(ctx.Home()).
+		WithInfo(info).
+		WithDNSGuard(body.DNSGuard).
+		WithKillSwitch(body.KillSwitch), nil
+}
+
+// routePeers keeps the endpoint of the entry hop off the tunnel and routes
+// the endpoint of each later hop through the hop before it, so that the
+// packets to the exit are wrapped once per hop. wg-quick keeps the packets
+// of the tunnel itself off a default route, by a rule that would send those
+// to the later hops out of the physical interface too. So a multi-hop
+// session has its default routes split in halves, which wg-quick routes like
+// any other network.
+func routePeers(peers []wireGuardPeer) []wireGuardPeer {
+	entry := []wgt.IPNet{newHostIPNet(peers[0].host)}
+	for i := range peers {
+		var items []wgt.IPNet
+		for _, item := range peers[i].allowedIPs {
+			switch {
+			case item.Net != 0:
+				items = append(items, item.Exclude(entry)...)
+			case len(peers) > 1:
+				left, right := item.Split()
+				items = append(items, left.Exclude(entry)...)
+				items = append(items, right.Exclude(entry)...)
+			default:
+				items = append(items, item)
+			}
+		}
+
+		if i+1 < len(peers) {
+			items = append(items, newHostIPNet(peers[i+1].host))
+		}
+
+		peers[i].allowedIPs = items
+	}
+
+	return peers
+}
+
+func newHostIPNet(ip net.IP) wgt.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		return wgt.IPNet{IP: v4, Net: 8 * net.IPv4len}
+	}
+
+	return wgt.IPNet{IP: ip, Net: 8 * net.IPv6len}
+}
+
+// newWireGuardReconnectFunc returns the func that asks the node for a new
+// session. It is called with the interface down, so the request is sent to
+// the node API address resolved at the start, which the kill switch lets
+// through, rather than through a proxy or the resolver of the tunnel.
+func newWireGuardReconnectFunc(ctx *context.Context, body *RequestAddSession, status *types.Status, endpoint string, fingerprint []byte, nodeAPI wgt.Endpoint) wireguard.ReconnectFunc {
+	attempts := body.Attempts
+	if attempts == 0 {
+		attempts = 3
+	}
+
+	return func(c gocontext.Context) (*wgt.Config, error) {
+		privateKey, err := wgt.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		defer privateKey.Zero()
+
+		client := pinNodeHTTPClient(newNodeHTTPClient(ctx, body, fingerprint), nodeAPI)
+
+		result, err := requestSession(c, client, endpoint, privateKey.Public().String(), attempts, body)
+		if err != nil {
+			return nil, err
+		}
+
+		service, err := newWireGuardService(ctx, body, status, privateKey, result)
+		if err != nil {
+			return nil, err
+		}
+
+		return service.(*wireguard.WireGuard).Config(), nil
+	}
+}
+
+// isRouted tells whether the node routes the network, as it does every
+// network when it names none.
+func isRouted(routed []wgt.IPNet, v wgt.IPNet) bool {
+	if len(routed) == 0 {
+		return true
+	}
+
+	for i := range routed {
+		if routed[i].Contains(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newAllowedIPs returns the networks routed through a single peer session,
+// the ones the node routes or everything when it names none, unless the
+// routing preset says otherwise, or the request excludes IPs or includes only
+// some domains.
+func newAllowedIPs(ctx *context.Context, body *RequestAddSession, routed []wgt.IPNet) ([]wgt.IPNet, error) {
+	if body.Routing == types.RoutingNone {
+		return nil, nil
+	}
+
+	allowedIPs := routed
+	if len(allowedIPs) == 0 {
+		allowedIPs = []wgt.IPNet{
+			wgt.DefaultRouteIPv4,
+			wgt.DefaultRouteIPv6,
+		}
+	}
+
+	var excluded []wgt.IPNet
+	for _, cidr := range body.ExcludedIPs {
+		ipNet, err := wgt.NewIPNetFromCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		excluded = append(excluded, *ipNet)
+	}
+	if body.Routing == types.RoutingLANExempt {
+		items, err := lanNetworks()
+		if err != nil {
+			return nil, err
+		}
+
+		excluded = append(excluded, items...)
+	}
+
+	if len(excluded) > 0 {
+		var items []wgt.IPNet
+		for i := range allowedIPs {
+			items = append(items, allowedIPs[i].Exclude(excluded)...)
+		}
+
+		allowedIPs = items
+	}
+
+	// Domains are resolved only once here, so the routes are not updated if
+	// their records change while the session is active. Addresses outside the
+	// networks the node routes are left off the tunnel.
+	if len(body.IncludedDomains) > 0 {
+		allowedIPs = nil
+
+		seen := make(map[string]bool)
+		for _, domain := range body.IncludedDomains {
+			ips, err := ctx.Resolver().LookupIP(ctx.Context(), "ip", domain)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, ip := range ips {
+				if seen[ip.String()] {
+					continue
+				}
+
+				seen[ip.String()] = true
+
+				item := wgt.IPNet{IP: ip, Net: 128}
+				if v4 := ip.To4(); v4 != nil {
+					item = wgt.IPNet{IP: v4, Net: 32}
+				}
+				if isRouted(routed, item) {
+					allowedIPs = append(allowedIPs, item)
+				}
+			}
+		}
+	}
+
+	return allowedIPs, nil
+}
+
+// wireGuardUpper is the part of a WireGuard service upWireGuard brings up.
+type wireGuardUpper interface {
+	PreUp() error
+	Up() error
+	Config() *wgt.Config
+}
+
+// upWireGuard brings the interface up, moving to another free port when the
+// one picked by GetFreeUDPPort was taken before wg-quick could bind it. It
+// gives up without bringing the interface up once the connect is cancelled.
+func upWireGuard(c gocontext.Context, service wireGuardUpper, attempts int) (err error) {
+	for i := 0; i < attempts; i++ {
+		if err := c.Err(); err != nil {
+			return err
+		}
+
+		if i > 0 {
+			port, err := utils.GetFreeUDPPort()
+			if err != nil {
+				return err
+			}
+
+			service.Config().Interface.ListenPort = port
+			if err := service.PreUp(); err != nil {
+				return err
+			}
+		}
+
+		if err = service.Up(); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func newV2RayService(ctx *context.Context, body *RequestAddSession, status *types.Status, uuid *v2raytypes.UUID, result []byte) (types.Service, error) {
+	var (
+		host, port = net.IP(result[0:4]), binary.BigEndian.Uint16(result[4:6])
+		transport  = v2raytypes.Transport(result[6])
+	)
+
+	if !transport.IsValid() {
+		return nil, fmt.Errorf("invalid transport %d", transport)
+	}
+
+	apiPort, err := utils.GetFreeTCPPort()
+	if err != nil {
+		return nil, err
+	}
+
+	proxyPort, err := utils.GetFreeTCPPort()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &v2raytypes.Config{
+		Name: fmt.Sprintf("%s%d", v2raytypes.DefaultName, status.ID),
+		API: v2raytypes.API{
+			Port: apiPort,
+		},
+		Proxy: v2raytypes.Proxy{
+			Port: proxyPort,
+		},
+		VMess: v2raytypes.VMess{
+			Address:   host.String(),
+			ID:        *uuid,
+			Port:      port,
+			Transport: transport,
+		},
+	}
+
+	info, err := json.Marshal(status.WithName(cfg.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return v2ray.NewV2Ray().
+		WithConfig(cfg).
+		WithConfigDir(ctx.Home()).
+		WithInfo(info), nil
+}
+
+func newResponseStartSession(id uint64, service types.Service) ResponseStartSession {
+	item := ResponseStartSession{
+		ID: id,
+	}
+
+	switch s := service.(type) {
+	case *wireguard.WireGuard:
+		cfg := s.Config()
+		item.Type = types.ServiceTypeWireGuard
+		item.Interface = cfg.Name
+		item.ListenPort = cfg.Interface.ListenPort
+		for _, address := range cfg.Interface.Addresses {
+			item.Addresses = append(item.Addresses, address.IP.String())
+		}
+		if len(cfg.Peers) > 0 {
+			item.Endpoint = ResponseEndpoint{
+				Host: cfg.Peers[0].Endpoint.Host,
+				Port: cfg.Peers[0].Endpoint.Port,
+			}
+		}
+	case *v2ray.V2Ray:
+		cfg := s.Config()
+		item.Type = types.ServiceTypeV2Ray
+		item.Interface = cfg.Name
+		item.ListenPort = cfg.Proxy.Port
+		item.Endpoint = ResponseEndpoint{
+			Host: cfg.VMess.Address,
+			Port: cfg.VMess.Port,
+		}
+	}
+
+	return item
+}
@@ -0,0 +1,445 @@
+package session
+
+import (
+	"bytes"
+	gocontext "context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func newTestContext(t *testing.T) *context.Context {
+	t.Helper()
+
+	cfg := types.NewConfig()
+	cfg.WireGuard.InterfacePrefix = "wg"
+
+	return context.NewContext().
+		WithConfig(cfg).
+		WithHome(t.TempDir())
+}
+
+func mustIPNet(t *testing.T, s string) wgt.IPNet {
+	t.Helper()
+
+	v, err := wgt.NewIPNetFromCIDR(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *v
+}
+
+// routedPeer returns the index of the peer WireGuard sends a packet for the
+// IP to, by the longest matching allowed IP, or -1 when none matches.
+func routedPeer(peers []wireGuardPeer, ip net.IP) int {
+	var (
+		host = newHostIPNet(ip)
+		peer = -1
+		best = -1
+	)
+
+	for i := range peers {
+		for _, item := range peers[i].allowedIPs {
+			if item.Contains(host) && int(item.Net) > best {
+				peer, best = i, int(item.Net)
+			}
+		}
+	}
+
+	return peer
+}
+
+func TestRoutePeersMultiHop(t *testing.T) {
+	var (
+		entry  = net.ParseIP("198.51.100.1").To4()
+		middle = net.ParseIP("198.51.100.2").To4()
+		exit   = net.ParseIP("203.0.113.3").To4()
+	)
+
+	peers := routePeers([]wireGuardPeer{
+		{host: entry, allowedIPs: []wgt.IPNet{mustIPNet(t, "10.0.0.1/32")}},
+		{host: middle, allowedIPs: []wgt.IPNet{mustIPNet(t, "10.0.0.2/32")}},
+		{host: exit, allowedIPs: []wgt.IPNet{wgt.DefaultRouteIPv4, wgt.DefaultRouteIPv6}},
+	})
+
+	tests := []struct {
+		name string
+		ip   net.IP
+		peer int
+	}{
+		{"entry endpoint is off the tunnel", entry, -1},
+		{"middle endpoint goes through the entry", middle, 0},
+		{"exit endpoint goes through the middle", exit, 1},
+		{"other traffic goes to the exit", net.ParseIP("1.1.1.1"), 2},
+		{"IPv6 traffic goes to the exit", net.ParseIP("2001:db8::1"), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if peer := routedPeer(peers, tt.ip); peer != tt.peer {
+				t.Fatalf("expected peer %d for %s, got %d", tt.peer, tt.ip, peer)
+			}
+		})
+	}
+
+	for i := range peers {
+		for _, item := range peers[i].allowedIPs {
+			if item.Net == 0 {
+				t.Errorf("peer %d keeps default route %s, which wg-quick routes past the later hops", i, item.String())
+			}
+		}
+	}
+}
+
+func TestRoutePeersSingleHop(t *testing.T) {
+	host := net.ParseIP("198.51.100.1").To4()
+
+	peers := routePeers([]wireGuardPeer{
+		{host: host, allowedIPs: []wgt.IPNet{wgt.DefaultRouteIPv4, mustIPNet(t, "198.51.100.0/24")}},
+	})
+
+	if got := peers[0].allowedIPs[0]; got.Net != 0 {
+		t.Fatalf("expected the default route kept for wg-quick, got %s", got.String())
+	}
+	for _, item := range peers[0].allowedIPs[1:] {
+		if item.Contains(newHostIPNet(host)) {
+			t.Fatalf("expected the endpoint excluded, got %s", item.String())
+		}
+	}
+}
+
+func TestDefaultDNS(t *testing.T) {
+	v4Addr := net.ParseIP("10.8.0.5").To4()
+
+	tests := []struct {
+		name     string
+		network  string
+		expected []string
+	}{
+		{"default network", "", []string{"10.8.0.1"}},
+		{"dual", types.NetworkDual, []string{"10.8.0.1"}},
+		{"IPv4", types.NetworkIPv4, []string{"10.8.0.1"}},
+		{"IPv6 gets none", types.NetworkIPv6, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dns := defaultDNS(tt.network, v4Addr)
+			if len(dns) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, dns)
+			}
+			for i := range dns {
+				if dns[i].String() != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, dns)
+				}
+			}
+		})
+	}
+}
+
+func TestNewWireGuardServiceDNS(t *testing.T) {
+	tests := []struct {
+		name     string
+		v4Addr   string
+		body     RequestAddSession
+		expected []string
+	}{
+		{"default", "10.8.0.2", RequestAddSession{}, []string{"10.8.0.1"}},
+		{"other tunnel subnet", "10.9.5.42", RequestAddSession{}, []string{"10.9.5.1"}},
+		{"explicit resolvers", "10.9.5.42", RequestAddSession{DNS: []string{"1.1.1.1", "2606:4700:4700::1111"}},
+			[]string{"1.1.1.1", "2606:4700:4700::1111"}},
+		{"nothing routed", "10.9.5.42", RequestAddSession{Routing: types.RoutingNone}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := wgt.NewPrivateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := testNodeResult{v4Addr: net.ParseIP(tt.v4Addr), host: net.ParseIP("203.0.113.1")}.bytes()
+			service, err := newWireGuardService(newTestContext(t), &tt.body, types.NewStatus().WithID(1), privateKey, result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dns := service.(*wireguard.WireGuard).Config().Interface.DNS
+			if len(dns) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, dns)
+			}
+			for i := range dns {
+				if dns[i].String() != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, dns)
+				}
+			}
+		})
+	}
+}
+
+// fakeWireGuard counts the interfaces it brings up, failing when up fails for
+// its listen port.
+type fakeWireGuard struct {
+	config *wgt.Config
+	up     func(port uint16) error
+	ups    int
+}
+
+func (f *fakeWireGuard) PreUp() error        { return nil }
+func (f *fakeWireGuard) Config() *wgt.Config { return f.config }
+
+func (f *fakeWireGuard) Up() error {
+	if f.up != nil {
+		if err := f.up(f.config.Interface.ListenPort); err != nil {
+			return err
+		}
+	}
+
+	f.ups++
+	return nil
+}
+
+func TestUpWireGuardCancelled(t *testing.T) {
+	c, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+
+	service := &fakeWireGuard{config: &wgt.Config{}}
+	if err := upWireGuard(c, service, 3); !errors.Is(err, gocontext.Canceled) {
+		t.Fatalf("expected %v, got %v", gocontext.Canceled, err)
+	}
+	if service.ups != 0 {
+		t.Fatalf("expected no interface to be brought up, got %d", service.ups)
+	}
+}
+
+func TestUpWireGuardPortTaken(t *testing.T) {
+	taken, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = taken.Close()
+	}()
+
+	// Up binds the listen port the way wg-quick does, failing while another
+	// process holds it.
+	bind := func(port uint16) error {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4zero, Port: int(port)})
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+
+	port := uint16(taken.LocalAddr().(*net.UDPAddr).Port)
+	tests := []struct {
+		name     string
+		attempts int
+		err      bool
+	}{
+		{"recovers on a fresh port", 3, false},
+		{"fails with a single attempt", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeWireGuard{
+				config: &wgt.Config{Interface: wgt.Interface{ListenPort: port}},
+				up:     bind,
+			}
+
+			err := upWireGuard(gocontext.Background(), service, tt.attempts)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if tt.err {
+				return
+			}
+			if service.ups != 1 {
+				t.Fatalf("expected the interface to be brought up once, got %d", service.ups)
+			}
+			if service.config.Interface.ListenPort == port {
+				t.Fatalf("expected a port other than the taken %d", port)
+			}
+		})
+	}
+}
+
+func TestNewWireGuardServiceEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        net.IP
+		excludedIPs []string
+		endpoint    string
+	}{
+		{"IPv4 host", net.ParseIP("203.0.113.1"), nil, "203.0.113.1:51820"},
+		{"IPv6 host", net.ParseIP("2001:db8::1"), nil, "[2001:db8::1]:51820"},
+		{"IPv4 host with split routes", net.ParseIP("203.0.113.1"), []string{"192.168.0.0/16"}, "203.0.113.1:51820"},
+		{"IPv6 host with split routes", net.ParseIP("2001:db8::1"), []string{"fd00::/8"}, "[2001:db8::1]:51820"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := wgt.NewPrivateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body := &RequestAddSession{ExcludedIPs: tt.excludedIPs}
+			result := testNodeResult{version: NodeResultVersionLegacy, host: tt.host}.bytes()
+
+			service, err := newWireGuardService(newTestContext(t), body, types.NewStatus().WithID(1), privateKey, result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			peers := service.(*wireguard.WireGuard).Config().Peers
+			if len(peers) != 1 {
+				t.Fatalf("expected 1 peer, got %d", len(peers))
+			}
+			if v := peers[0].Endpoint.String(); v != tt.endpoint {
+				t.Errorf("expected endpoint %s, got %s", tt.endpoint, v)
+			}
+
+			host := newHostIPNet(tt.host)
+			for _, item := range peers[0].AllowedIPs {
+				if item.Net == 0 && len(tt.excludedIPs) > 0 && item.IsIPv4() == (tt.host.To4() != nil) {
+					t.Errorf("expected split routes, got default route %s", item.String())
+				}
+				if item.Net != 0 && item.Contains(host) {
+					t.Errorf("allowed IP %s routes the endpoint through the tunnel", item.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNewWireGuardServiceDefaultRoutes(t *testing.T) {
+	privateKey, err := wgt.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := testNodeResult{version: NodeResultVersionLegacy, host: net.ParseIP("203.0.113.1")}.bytes()
+	service, err := newWireGuardService(newTestContext(t), &RequestAddSession{}, types.NewStatus().WithID(1), privateKey, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := service.(*wireguard.WireGuard).Config().ToWgQuick()
+	if line := "AllowedIPs = 0.0.0.0/0, ::/0\n"; !strings.Contains(output, line) {
+		t.Fatalf("expected line %q in\n%s", line, output)
+	}
+}
+
+func TestNewAllowedIPsExcludedIPs(t *testing.T) {
+	body := &RequestAddSession{
+		ExcludedIPs: []string{"192.168.0.0/16", "10.0.0.0/8", "fd00::/8"},
+	}
+
+	items, err := newAllowedIPs(newTestContext(t), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := newAllowedIPs(newTestContext(t), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != len(again) {
+		t.Fatalf("expected the same allowed IPs, got %d and %d", len(items), len(again))
+	}
+
+	for i := range items {
+		if items[i].String() != again[i].String() {
+			t.Fatalf("expected the same allowed IPs, got %s and %s at %d", items[i].String(), again[i].String(), i)
+		}
+		for _, cidr := range body.ExcludedIPs {
+			if excluded := mustIPNet(t, cidr); items[i].Overlaps(excluded) {
+				t.Errorf("allowed IP %s overlaps the excluded %s", items[i].String(), cidr)
+			}
+		}
+		for j := i + 1; j < len(items); j++ {
+			if items[i].Overlaps(items[j]) {
+				t.Errorf("allowed IP %s overlaps %s", items[i].String(), items[j].String())
+			}
+		}
+	}
+
+	for _, ip := range []string{"1.1.1.1", "172.16.0.1", "2606:4700:4700::1111"} {
+		if !isRouted(items, newHostIPNet(net.ParseIP(ip))) {
+			t.Errorf("expected %s to be routed through the tunnel", ip)
+		}
+	}
+}
+
+// testMultiHopResult returns the result of a session with a peer per host,
+// each routed its own host.
+func testMultiHopResult(hosts ...net.IP) []byte {
+	result := append(append([]byte{}, net.ParseIP("10.8.0.2").To4()...), net.ParseIP("fd86:ea04:1115::2").To16()...)
+	result = append(result, byte(len(hosts)))
+	for i, host := range hosts {
+		host = host.To4()
+		result = append(result, byte(len(host)))
+		result = append(result, host...)
+		result = append(result, 0xca, 0x6c)
+		result = append(result, bytes.Repeat([]byte{byte(i + 1)}, wgt.KeyLength)...)
+		result = append(result, 0, 1, byte(len(host)))
+		result = append(result, host...)
+		result = append(result, 32)
+	}
+
+	return result
+}
+
+func TestCheckResultTruncated(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      string
+		hops   uint64
+		result []byte
+	}{
+		{"legacy IPv4 host", types.ServiceTypeWireGuard, 1, testNodeResult{host: net.ParseIP("203.0.113.1")}.bytes()},
+		{"legacy IPv6 host", types.ServiceTypeWireGuard, 1, testNodeResult{host: net.ParseIP("2001:db8::1")}.bytes()},
+		{"v2", types.ServiceTypeWireGuard, 1, testNodeResult{version: NodeResultVersion2, host: net.ParseIP("203.0.113.1"), presharedKey: true}.bytes()},
+		{"multi-hop", types.ServiceTypeWireGuard, 2, testMultiHopResult(net.ParseIP("198.51.100.1"), net.ParseIP("203.0.113.1"))},
+		{"V2Ray", types.ServiceTypeV2Ray, 1, []byte{203, 0, 113, 1, 0x1f, 0x90, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkResult(tt.t, tt.hops, tt.result); err != nil {
+				t.Fatalf("expected the whole result to be accepted, got %v", err)
+			}
+
+			for n := 0; n < len(tt.result); n++ {
+				if err := checkResult(tt.t, tt.hops, tt.result[:n]); err == nil {
+					t.Fatalf("expected the result truncated to %d bytes to be rejected", n)
+				}
+			}
+		})
+	}
+}
+
+func TestNewWireGuardServiceTruncated(t *testing.T) {
+	result := testNodeResult{host: net.ParseIP("203.0.113.1")}.bytes()
+	for n := 0; n < len(result); n++ {
+		privateKey, err := wgt.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := newWireGuardService(newTestContext(t), &RequestAddSession{}, types.NewStatus().WithID(1), privateKey, result[:n]); err == nil {
+			t.Fatalf("expected the result truncated to %d bytes to be rejected", n)
+		}
+	}
+}
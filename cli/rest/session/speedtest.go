@@ -0,0 +1,185 @@
+package session
+
+import (
+	"bytes"
+	gocontext "context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/sentinel-official/desktop-client/cli/services/v2ray"
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard"
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+const (
+	defaultSpeedTestDownloadURL = "https://speed.cloudflare.com/__down?bytes=%d"
+	defaultSpeedTestUploadURL   = "https://speed.cloudflare.com/__up"
+	defaultSpeedTestSize        = 10 << 20
+	defaultSpeedTestDuration    = 10
+	maxSpeedTestSize            = 100 << 20
+	maxSpeedTestDuration        = 60
+)
+
+type dialFunc func(ctx gocontext.Context, network, address string) (net.Conn, error)
+
+func newTunnelDialFunc(service types.Service) (dialFunc, error) {
+	switch s := service.(type) {
+	case *wireguard.WireGuard:
+		dialer, err := s.Dialer()
+		if err != nil {
+			return nil, err
+		}
+
+		return dialer.DialContext, nil
+	case *v2ray.V2Ray:
+		dialer, err := proxy.SOCKS5("tcp", fmt.Sprintf("127.0.0.1:%d", s.Config().Proxy.Port), nil, proxy.Direct)
+		if err != nil {
+			return nil, err
+		}
+
+		return dialer.(proxy.ContextDialer).DialContext, nil
+	default:
+		return nil, fmt.Errorf("unsupported service %T", service)
+	}
+}
+
+func measureRTT(c gocontext.Context, dial dialFunc, rawURL string) (time.Duration, error) {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	start := time.Now()
+	conn, err := dial(c, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return 0, err
+	}
+
+	rtt := time.Since(start)
+	_ = conn.Close()
+
+	return rtt, nil
+}
+
+func measureDownload(c gocontext.Context, client *http.Client, rawURL string, size int64) (float64, error) {
+	req, err := http.NewRequestWithContext(c, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	n, err := io.CopyN(ioutil.Discard, resp.Body, size)
+	if err != nil && err != io.EOF && n == 0 {
+		return 0, err
+	}
+
+	return mbps(n, time.Since(start)), nil
+}
+
+func measureUpload(c gocontext.Context, client *http.Client, rawURL string, size int64) (float64, error) {
+	req, err := http.NewRequestWithContext(c, http.MethodPost, rawURL, bytes.NewReader(make([]byte, size)))
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = resp.Body.Close()
+	return mbps(size, time.Since(start)), nil
+}
+
+func mbps(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+
+	return float64(n) * 8 / d.Seconds() / 1e6
+}
+
+func runSpeedTest(c gocontext.Context, service types.Service, body *RequestSpeedTest) (*ResponseSpeedTest, error) {
+	var (
+		size     = int64(defaultSpeedTestSize)
+		duration = defaultSpeedTestDuration * time.Second
+	)
+
+	if body.Size > 0 {
+		size = int64(body.Size)
+	}
+	if body.Duration > 0 {
+		duration = time.Duration(body.Duration) * time.Second
+	}
+
+	downloadURL, uploadURL := body.DownloadURL, body.UploadURL
+	if downloadURL == "" {
+		downloadURL = fmt.Sprintf(defaultSpeedTestDownloadURL, size)
+	}
+	if uploadURL == "" {
+		uploadURL = defaultSpeedTestUploadURL
+	}
+
+	dial, err := newTunnelDialFunc(service)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: dial,
+		},
+	}
+
+	rtt, err := measureRTT(c, dial, downloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, cancel := gocontext.WithTimeout(c, duration)
+	defer cancel()
+
+	download, err := measureDownload(dc, client, downloadURL, size)
+	if err != nil {
+		return nil, err
+	}
+
+	uc, cancel := gocontext.WithTimeout(c, duration)
+	defer cancel()
+
+	upload, err := measureUpload(uc, client, uploadURL, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResponseSpeedTest{
+		DownloadMbps: download,
+		UploadMbps:   upload,
+		RTT:          float64(rtt.Microseconds()) / 1e3,
+	}, nil
+}
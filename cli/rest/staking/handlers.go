@@ -44,7 +44,7 @@ func HandlerGetValidators(ctx *context.Context) http.HandlerFunc {
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1001, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
 			return
 		}
 
@@ -126,9 +126,9 @@ func HandlerDelegate(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1006, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1006, err)
 			return
 		}
 
@@ -178,9 +178,9 @@ func HandlerRedelegate(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1006, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1006, err)
 			return
 		}
 
@@ -228,9 +228,9 @@ func HandlerUndelegate(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1006, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1006, err)
 			return
 		}
 
@@ -6,11 +6,12 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/sentinel-official/desktop-client/cli/types"
 	"github.com/sentinel-official/desktop-client/cli/x/common"
 )
 
 type RequestDelegate struct {
-	Memo string      `json:"memo"`
+	types.TxOptions
 	To   string      `json:"to"`
 	Coin common.Coin `json:"coin"`
 }
@@ -25,6 +26,9 @@ func NewRequestDelegate(r *http.Request) (*RequestDelegate, error) {
 }
 
 func (r *RequestDelegate) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if _, err := sdk.ValAddressFromBech32(r.To); err != nil {
 		return err
 	}
@@ -33,7 +37,7 @@ func (r *RequestDelegate) Validate() error {
 }
 
 type RequestRedelegate struct {
-	Memo string      `json:"memo"`
+	types.TxOptions
 	From string      `json:"from"`
 	To   string      `json:"to"`
 	Coin common.Coin `json:"coin"`
@@ -49,6 +53,9 @@ func NewRequestRedelegate(r *http.Request) (*RequestRedelegate, error) {
 }
 
 func (r *RequestRedelegate) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if _, err := sdk.ValAddressFromBech32(r.From); err != nil {
 		return err
 	}
@@ -60,7 +67,7 @@ func (r *RequestRedelegate) Validate() error {
 }
 
 type RequestUnbond struct {
-	Memo string      `json:"memo"`
+	types.TxOptions
 	From string      `json:"from"`
 	Coin common.Coin `json:"coin"`
 }
@@ -75,6 +82,9 @@ func NewRequestUnbond(r *http.Request) (*RequestUnbond, error) {
 }
 
 func (r *RequestUnbond) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if _, err := sdk.ValAddressFromBech32(r.From); err != nil {
 		return err
 	}
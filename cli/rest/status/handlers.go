@@ -0,0 +1,28 @@
+package status
+
+import (
+	"net/http"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+func HandlerGetSyncStatus(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := ctx.Client().SyncStatus()
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusServiceUnavailable, 1001, err.Error())
+			return
+		}
+
+		item := ResponseSyncStatus{
+			LatestBlockHeight: res.LatestBlockHeight,
+			LatestBlockTime:   res.LatestBlockTime,
+			CatchingUp:        res.CatchingUp,
+			Stale:             res.Stale,
+			Synced:            !res.CatchingUp && !res.Stale,
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
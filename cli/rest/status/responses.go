@@ -0,0 +1,13 @@
+package status
+
+import (
+	"time"
+)
+
+type ResponseSyncStatus struct {
+	LatestBlockHeight int64     `json:"latest_block_height"`
+	LatestBlockTime   time.Time `json:"latest_block_time"`
+	CatchingUp        bool      `json:"catching_up"`
+	Stale             bool      `json:"stale"`
+	Synced            bool      `json:"synced"`
+}
@@ -0,0 +1,15 @@
+package status
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("GetSyncStatus").
+		Methods(http.MethodGet, http.MethodHead).Path("/status").
+		HandlerFunc(HandlerGetSyncStatus(ctx))
+}
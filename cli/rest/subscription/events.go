@@ -0,0 +1,38 @@
+package subscription
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+	subscriptiontypes "github.com/sentinel-official/hub/x/subscription/types"
+)
+
+var (
+	subscribeEventTypes = []string{
+		proto.MessageName(&subscriptiontypes.EventSubscribeToNode{}),
+		proto.MessageName(&subscriptiontypes.EventSubscribeToPlan{}),
+	}
+)
+
+func subscriptionIDFromTxResponse(res *sdk.TxResponse) (uint64, error) {
+	for _, log := range res.Logs {
+		for _, event := range log.Events {
+			for _, t := range subscribeEventTypes {
+				if event.Type != t {
+					continue
+				}
+
+				for _, attribute := range event.Attributes {
+					if attribute.Key == "id" {
+						return strconv.ParseUint(strings.Trim(attribute.Value, `"`), 10, 64)
+					}
+				}
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("subscription id not found in the transaction events")
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sentinel-official/desktop-client/cli/context"
 	"github.com/sentinel-official/desktop-client/cli/utils"
+	"github.com/sentinel-official/desktop-client/cli/x/session"
 	"github.com/sentinel-official/desktop-client/cli/x/subscription"
 )
 
@@ -42,7 +43,6 @@ func HandlerGetSubscriptionsForAddress(ctx *context.Context) http.HandlerFunc {
 		var (
 			values = r.URL.Query()
 			vars   = mux.Vars(r)
-			status = hubtypes.StatusFromString(values.Get("status"))
 		)
 
 		address, err := sdk.AccAddressFromBech32(vars["address"])
@@ -57,17 +57,34 @@ func HandlerGetSubscriptionsForAddress(ctx *context.Context) http.HandlerFunc {
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1003, err.Error())
+			return
+		}
+
+		status, err := utils.ParseStatusQuery(values)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1004, err.Error())
 			return
 		}
 
 		res, err := ctx.Client().QuerySubscriptionsForAddress(address, status, pagination)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1005, err.Error())
 			return
 		}
 
-		items := subscription.NewSubscriptionsFromRaw(res)
+		items := make([]ResponseSubscription, 0, len(res))
+		for i := range res {
+			items = append(items, ResponseSubscription{
+				Subscription: subscription.NewSubscriptionFromRaw(&res[i]),
+			})
+		}
+
+		client := ctx.Client()
+		addQuotas(items, func(id uint64) (*subscriptiontypes.Quota, error) {
+			return client.QueryQuota(id, address)
+		})
+
 		utils.WriteResultToResponse(w, http.StatusOK, items)
 	}
 }
@@ -127,13 +144,24 @@ func HandlerAddSubscription(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1006, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1006, err)
 			return
 		}
 
-		utils.WriteResultToResponse(w, http.StatusOK, res)
+		id, err := subscriptionIDFromTxResponse(res)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1007, err.Error())
+			return
+		}
+
+		utils.WriteResultToResponse(w, http.StatusOK,
+			ResponseAddSubscription{
+				ID:     id,
+				TxHash: res.TxHash,
+			},
+		)
 	}
 }
 
@@ -143,14 +171,17 @@ func HandlerCancelSubscription(ctx *context.Context) http.HandlerFunc {
 			vars = mux.Vars(r)
 		)
 
-		address, err := sdk.AccAddressFromBech32(vars["address"])
-		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
-			return
-		}
-		if !ctx.Client().FromAddress().Equals(address) {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, "")
-			return
+		from := ctx.Client().FromAddress()
+		if vars["address"] != "" {
+			address, err := sdk.AccAddressFromBech32(vars["address"])
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+				return
+			}
+			if !from.Equals(address) {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, "")
+				return
+			}
 		}
 
 		id, err := strconv.ParseUint(vars["id"], 10, 64)
@@ -169,9 +200,27 @@ func HandlerCancelSubscription(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
+		current, err := ctx.Client().QuerySubscription(id)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1008, err.Error())
+			return
+		}
+		if current == nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1009, "subscription does not exist")
+			return
+		}
+		if current.Owner != from.String() {
+			utils.WriteErrorToResponse(w, http.StatusForbidden, 1010, "subscription is not owned by the address")
+			return
+		}
+		if !current.Status.Equal(hubtypes.StatusActive) {
+			utils.WriteErrorToResponse(w, http.StatusConflict, 1011, "subscription is already cancelled")
+			return
+		}
+
 		var (
 			message = subscriptiontypes.NewMsgCancelRequest(
-				ctx.Client().FromAddress(),
+				from,
 				id,
 			)
 		)
@@ -181,12 +230,13 @@ func HandlerCancelSubscription(ctx *context.Context) http.HandlerFunc {
 			return
 		}
 
-		res, err := ctx.Client().BroadcastTx(body.Memo, message)
+		res, err := ctx.Client().BroadcastTx(body.TxOptions, message)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1007, err.Error())
+			utils.WriteBroadcastErrorToResponse(w, 1007, err)
 			return
 		}
 
+		stopSessionsForSubscription(ctx, id)
 		utils.WriteResultToResponse(w, http.StatusOK, res)
 	}
 }
@@ -197,14 +247,17 @@ func HandlerGetQuota(ctx *context.Context) http.HandlerFunc {
 			vars = mux.Vars(r)
 		)
 
-		address, err := sdk.AccAddressFromBech32(vars["address"])
-		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
-			return
-		}
-		if !ctx.Client().FromAddress().Equals(address) {
-			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, "")
-			return
+		address := ctx.Client().FromAddress()
+		if vars["address"] != "" {
+			v, err := sdk.AccAddressFromBech32(vars["address"])
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+				return
+			}
+			if !address.Equals(v) {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, "")
+				return
+			}
 		}
 
 		id, err := strconv.ParseUint(vars["id"], 10, 64)
@@ -218,6 +271,10 @@ func HandlerGetQuota(ctx *context.Context) http.HandlerFunc {
 			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1004, err.Error())
 			return
 		}
+		if res == nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1005, "quota does not exist for the address")
+			return
+		}
 
 		item := subscription.NewQuotaFromRaw(res)
 		utils.WriteResultToResponse(w, http.StatusOK, item)
@@ -239,7 +296,7 @@ func HandlerGetQuotas(ctx *context.Context) http.HandlerFunc {
 
 		pagination, err := utils.ParsePaginationQuery(values)
 		if err != nil {
-			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1002, err.Error())
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
 			return
 		}
 
@@ -253,3 +310,40 @@ func HandlerGetQuotas(ctx *context.Context) http.HandlerFunc {
 		utils.WriteResultToResponse(w, http.StatusOK, items)
 	}
 }
+
+func HandlerGetSessionForSubscription(ctx *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			values = r.URL.Query()
+			vars   = mux.Vars(r)
+		)
+
+		id, err := strconv.ParseUint(vars["id"], 10, 64)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusBadRequest, 1001, err.Error())
+			return
+		}
+
+		address := ctx.Client().FromAddress()
+		if values.Get("address") != "" {
+			address, err = sdk.AccAddressFromBech32(values.Get("address"))
+			if err != nil {
+				utils.WriteErrorToResponse(w, http.StatusBadRequest, 1002, err.Error())
+				return
+			}
+		}
+
+		res, err := ctx.Client().QuerySessionForSubscription(id, address)
+		if err != nil {
+			utils.WriteErrorToResponse(w, http.StatusInternalServerError, 1003, err.Error())
+			return
+		}
+		if res == nil {
+			utils.WriteErrorToResponse(w, http.StatusNotFound, 1004, "active session does not exist for the subscription")
+			return
+		}
+
+		item := session.NewSessionFromRaw(res)
+		utils.WriteResultToResponse(w, http.StatusOK, item)
+	}
+}
@@ -0,0 +1,50 @@
+package subscription
+
+import (
+	"fmt"
+	"sync"
+
+	subscriptiontypes "github.com/sentinel-official/hub/x/subscription/types"
+
+	"github.com/sentinel-official/desktop-client/cli/x/subscription"
+)
+
+const (
+	quotaConcurrency = 8
+)
+
+// queryQuotaFunc queries the quota of the address on the subscription.
+type queryQuotaFunc func(id uint64) (*subscriptiontypes.Quota, error)
+
+// addQuotas sets the quota of each item, querying no more than
+// quotaConcurrency of them at once. The hub has no query for the quotas of an
+// address across subscriptions, so there is one per item, bounded by the page
+// size. A failed query is reported on its item rather than failing the rest.
+func addQuotas(items []ResponseSubscription, query queryQuotaFunc) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, quotaConcurrency)
+	)
+
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			quota, err := query(items[i].Id)
+			if err != nil {
+				items[i].Error = fmt.Sprintf("failed to query quota: %s", err)
+				return
+			}
+			if quota != nil {
+				v := subscription.NewQuotaFromRaw(quota)
+				items[i].Quota = &v
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
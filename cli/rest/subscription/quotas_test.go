@@ -0,0 +1,60 @@
+package subscription
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	subscriptiontypes "github.com/sentinel-official/hub/x/subscription/types"
+
+	"github.com/sentinel-official/desktop-client/cli/x/subscription"
+)
+
+func TestAddQuotas(t *testing.T) {
+	items := make([]ResponseSubscription, 0, 3*quotaConcurrency)
+	for id := uint64(1); id <= 3*quotaConcurrency; id++ {
+		items = append(items, ResponseSubscription{Subscription: subscription.Subscription{Id: id}})
+	}
+
+	var running, peak int32
+	addQuotas(items, func(id uint64) (*subscriptiontypes.Quota, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			v := atomic.LoadInt32(&peak)
+			if n <= v || atomic.CompareAndSwapInt32(&peak, v, n) {
+				break
+			}
+		}
+
+		switch id % 3 {
+		case 0:
+			return nil, errors.New("unavailable")
+		case 1:
+			return nil, nil
+		default:
+			return &subscriptiontypes.Quota{Allocated: sdk.NewInt(10), Consumed: sdk.NewInt(4)}, nil
+		}
+	})
+
+	if peak > quotaConcurrency {
+		t.Fatalf("expected at most %d queries at once, got %d", quotaConcurrency, peak)
+	}
+	for _, item := range items {
+		switch item.Id % 3 {
+		case 0:
+			if item.Error == "" || item.Quota != nil {
+				t.Fatalf("expected an error and no quota for subscription %d, got %+v", item.Id, item)
+			}
+		case 1:
+			if item.Error != "" || item.Quota != nil {
+				t.Fatalf("expected neither an error nor a quota for subscription %d, got %+v", item.Id, item)
+			}
+		default:
+			if item.Error != "" || item.Quota == nil || item.Quota.Remaining != 6 {
+				t.Fatalf("expected a remaining quota of 6 for subscription %d, got %+v", item.Id, item)
+			}
+		}
+	}
+}
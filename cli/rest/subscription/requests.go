@@ -6,10 +6,12 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	hubtypes "github.com/sentinel-official/hub/types"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
 type RequestAddSubscription struct {
-	Memo  string `json:"memo"`
+	types.TxOptions
 	To    string `json:"to"`
 	Coin  string `json:"coin"`
 	ID    uint64 `json:"id"`
@@ -26,6 +28,9 @@ func NewRequestAddSubscription(r *http.Request) (*RequestAddSubscription, error)
 }
 
 func (r *RequestAddSubscription) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	if r.To != "" {
 		if _, err := hubtypes.NodeAddressFromBech32(r.To); err != nil {
 			return err
@@ -46,7 +51,7 @@ func (r *RequestAddSubscription) Validate() error {
 }
 
 type RequestCancelSubscription struct {
-	Memo string `json:"memo"`
+	types.TxOptions
 }
 
 func NewRequestCancelSubscription(r *http.Request) (*RequestCancelSubscription, error) {
@@ -59,5 +64,8 @@ func NewRequestCancelSubscription(r *http.Request) (*RequestCancelSubscription,
 }
 
 func (r *RequestCancelSubscription) Validate() error {
+	if err := r.TxOptions.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,16 @@
+package subscription
+
+import (
+	"github.com/sentinel-official/desktop-client/cli/x/subscription"
+)
+
+type ResponseSubscription struct {
+	subscription.Subscription
+	Quota *subscription.Quota `json:"quota,omitempty"`
+	Error string              `json:"error,omitempty"`
+}
+
+type ResponseAddSubscription struct {
+	ID     uint64 `json:"id"`
+	TxHash string `json:"tx_hash"`
+}
@@ -22,11 +22,21 @@ func RegisterRoutes(r *mux.Router, ctx *context.Context) {
 	r.Name("CancelSubscription").
 		Methods(http.MethodPost).Path("/accounts/{address}/subscriptions/{id}/cancel").
 		HandlerFunc(HandlerCancelSubscription(ctx))
+	r.Name("CancelOwnSubscription").
+		Methods(http.MethodPost).Path("/subscriptions/{id}/cancel").
+		HandlerFunc(HandlerCancelSubscription(ctx))
 
+	r.Name("GetOwnQuota").
+		Methods(http.MethodGet).Path("/subscriptions/{id}/quota").
+		HandlerFunc(HandlerGetQuota(ctx))
 	r.Name("GetQuota").
 		Methods(http.MethodGet).Path("/subscriptions/{id}/quotas/{address}").
 		HandlerFunc(HandlerGetQuota(ctx))
 	r.Name("GetQuotas").
 		Methods(http.MethodGet).Path("/subscriptions/{id}/quotas").
 		HandlerFunc(HandlerGetQuotas(ctx))
+
+	r.Name("GetSessionForSubscription").
+		Methods(http.MethodGet).Path("/subscriptions/{id}/session").
+		HandlerFunc(HandlerGetSessionForSubscription(ctx))
 }
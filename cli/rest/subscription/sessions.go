@@ -0,0 +1,23 @@
+package subscription
+
+import (
+	"log"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/rest/session"
+)
+
+// stopSessionsForSubscription stops the local session started on the
+// subscription, once it has been cancelled. Services are keyed by
+// subscription, so no chain query is needed to find it.
+func stopSessionsForSubscription(ctx *context.Context, id uint64) {
+	service := ctx.Service(id)
+	if service == nil {
+		return
+	}
+
+	log.Printf("Stopping the session of subscription %d: subscription is cancelled", id)
+	if err := session.StopSession(ctx, id, service); err != nil {
+		log.Printf("Failed to stop the session of subscription %d: %s", id, err)
+	}
+}
@@ -0,0 +1,15 @@
+package version
+
+import (
+	"net/http"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+	"github.com/sentinel-official/desktop-client/cli/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+func HandlerGetClientVersion(_ *context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteResultToResponse(w, http.StatusOK, types.NewBuildInfo())
+	}
+}
@@ -0,0 +1,15 @@
+package version
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sentinel-official/desktop-client/cli/context"
+)
+
+func RegisterRoutes(r *mux.Router, ctx *context.Context) {
+	r.Name("GetClientVersion").
+		Methods(http.MethodGet).Path("/version").
+		HandlerFunc(HandlerGetClientVersion(ctx))
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var (
+	ct = strings.TrimSpace(`
+{
+    "api": {
+        "services": [
+            "StatsService"
+        ],
+        "tag": "api"
+    },
+    "inbounds": [
+        {
+            "listen": "127.0.0.1",
+            "port": {{ .API.Port }},
+            "protocol": "dokodemo-door",
+            "settings": {
+                "address": "127.0.0.1"
+            },
+            "tag": "api"
+        },
+        {
+            "listen": "127.0.0.1",
+            "port": {{ .Proxy.Port }},
+            "protocol": "socks",
+            "settings": {
+                "ip": "127.0.0.1",
+                "udp": true
+            },
+            "sniffing": {
+                "destOverride": [
+                    "http",
+                    "tls"
+                ],
+                "enabled": true
+            },
+            "tag": "proxy"
+        }
+    ],
+    "log": {
+        "loglevel": "none"
+    },
+    "outbounds": [
+        {
+            "protocol": "vmess",
+            "settings": {
+                "vnext": [
+                    {
+                        "address": "{{ .VMess.Address }}",
+                        "port": {{ .VMess.Port }},
+                        "users": [
+                            {
+                                "alterId": 0,
+                                "id": "{{ .VMess.ID }}"
+                            }
+                        ]
+                    }
+                ]
+            },
+            "streamSettings": {
+                "network": "{{ .VMess.Transport }}"
+            },
+            "tag": "vmess"
+        }
+    ],
+    "policy": {
+        "system": {
+            "statsOutboundDownlink": true,
+            "statsOutboundUplink": true
+        }
+    },
+    "routing": {
+        "rules": [
+            {
+                "inboundTag": [
+                    "api"
+                ],
+                "outboundTag": "api",
+                "type": "field"
+            }
+        ]
+    },
+    "stats": {}
+}
+	`)
+
+	t = func() *template.Template {
+		t, err := template.New("v2ray").Parse(ct)
+		if err != nil {
+			panic(err)
+		}
+
+		return t
+	}()
+)
+
+type API struct {
+	Port uint16
+}
+
+type Proxy struct {
+	Port uint16
+}
+
+type VMess struct {
+	Address   string
+	ID        UUID
+	Port      uint16
+	Transport Transport
+}
+
+type Config struct {
+	Name  string
+	API   API
+	Proxy Proxy
+	VMess VMess
+}
+
+func (c *Config) ToJSON() (string, error) {
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, c); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func (c *Config) WriteToFile(dir string) error {
+	data, err := c.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(
+		filepath.Join(dir, fmt.Sprintf("%s.json", c.Name)),
+		[]byte(data),
+		0600,
+	)
+}
@@ -0,0 +1,42 @@
+package types
+
+type Transport byte
+
+const (
+	TransportUnspecified Transport = iota
+	TransportDomainSocket
+	TransportGUN
+	TransportGRPC
+	TransportHTTP
+	TransportMKCP
+	TransportQUIC
+	TransportTCP
+	TransportWebSocket
+)
+
+func (t Transport) IsValid() bool {
+	return t > TransportUnspecified && t <= TransportWebSocket
+}
+
+func (t Transport) String() string {
+	switch t {
+	case TransportDomainSocket:
+		return "domainsocket"
+	case TransportGUN:
+		return "gun"
+	case TransportGRPC:
+		return "grpc"
+	case TransportHTTP:
+		return "http"
+	case TransportMKCP:
+		return "mkcp"
+	case TransportQUIC:
+		return "quic"
+	case TransportTCP:
+		return "tcp"
+	case TransportWebSocket:
+		return "websocket"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	UUIDLength = 16
+)
+
+type UUID [UUIDLength]byte
+
+func NewUUID() (*UUID, error) {
+	var uuid UUID
+
+	_, err := rand.Read(uuid[:])
+	if err != nil {
+		return nil, err
+	}
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return &uuid, nil
+}
+
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
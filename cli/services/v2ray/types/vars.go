@@ -0,0 +1,5 @@
+package types
+
+const (
+	DefaultName = "v2ray"
+)
@@ -0,0 +1,113 @@
+package v2ray
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sentinel-official/desktop-client/cli/services/v2ray/types"
+)
+
+type V2Ray struct {
+	cfg    *types.Config
+	cfgDir string
+	info   []byte
+	cmd    *exec.Cmd
+}
+
+func NewV2Ray() *V2Ray {
+	return &V2Ray{}
+}
+
+func (s *V2Ray) WithConfig(v *types.Config) *V2Ray { s.cfg = v; return s }
+func (s *V2Ray) WithConfigDir(v string) *V2Ray     { s.cfgDir = v; return s }
+func (s *V2Ray) WithInfo(v []byte) *V2Ray          { s.info = v; return s }
+
+func (s *V2Ray) Config() *types.Config { return s.cfg }
+func (s *V2Ray) Info() []byte          { return s.info }
+
+func (s *V2Ray) configFilePath() string {
+	return filepath.Join(s.cfgDir, fmt.Sprintf("%s.json", s.cfg.Name))
+}
+
+func (s *V2Ray) PreUp() error {
+	return s.cfg.WriteToFile(s.cfgDir)
+}
+
+func (s *V2Ray) Up() error {
+	s.cmd = exec.Command("v2ray", strings.Split(
+		fmt.Sprintf("-config %s", s.configFilePath()), " ")...)
+	s.cmd.Stdout = os.Stdout
+	s.cmd.Stderr = os.Stderr
+
+	return s.cmd.Start()
+}
+
+func (s *V2Ray) PostUp() error  { return nil }
+func (s *V2Ray) PreDown() error { return nil }
+
+func (s *V2Ray) Down() error {
+	if s.cmd == nil || s.cmd.Process == nil {
+		return nil
+	}
+
+	if err := s.cmd.Process.Kill(); err != nil {
+		return err
+	}
+
+	_ = s.cmd.Wait()
+	s.cmd = nil
+
+	return nil
+}
+
+func (s *V2Ray) PostDown() error {
+	path := s.configFilePath()
+	if _, err := os.Stat(path); err == nil {
+		return os.Remove(path)
+	}
+
+	return nil
+}
+
+func (s *V2Ray) Transfer() (int64, int64, error) {
+	output, err := exec.Command("v2ctl", "api",
+		fmt.Sprintf("--server=127.0.0.1:%d", s.cfg.API.Port),
+		"StatsService.QueryStats",
+		`pattern: "vmess" reset: false`,
+	).Output()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		name             string
+		download, upload int64
+		lines            = strings.Split(string(output), "\n")
+	)
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "name:"):
+			name = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "name:")), `"`)
+		case strings.HasPrefix(line, "value:"):
+			value, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "value:")), 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			switch {
+			case strings.HasSuffix(name, ">>>downlink"):
+				download = value
+			case strings.HasSuffix(name, ">>>uplink"):
+				upload = value
+			}
+		}
+	}
+
+	return download, upload, nil
+}
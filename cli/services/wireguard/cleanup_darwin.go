@@ -0,0 +1,21 @@
+package wireguard
+
+import (
+	"fmt"
+	"os"
+)
+
+// cleanup removes the name and socket files wg-quick keeps for the interface.
+// Routes through the utun device go with it, and the DNS is restored by
+// PostDown when the DNS guard set it.
+func (w *WireGuard) cleanup() []error {
+	var errs []error
+	for _, ext := range []string{"name", "sock"} {
+		path := fmt.Sprintf("/var/run/wireguard/%s.%s", w.cfg.Name, ext)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
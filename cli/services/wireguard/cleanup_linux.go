@@ -0,0 +1,107 @@
+package wireguard
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+// cleanup undoes what wg-quick up changed besides adding the interface: the
+// forwarding rules of PreUp, the DNS set through resolvconf, and the policy
+// routing of a default route. Steps whose changes may already be gone ignore
+// their failures.
+func (w *WireGuard) cleanup() []error {
+	var (
+		errs []error
+		name = w.cfg.Name
+	)
+
+	cfg, err := types.NewConfigFromFile(filepath.Join(w.cfgDir, fmt.Sprintf("%s.conf", name)))
+	if err == nil {
+		cfg.Interface.PrivateKey.Zero()
+		for _, command := range strings.Split(cfg.Interface.PostDown, ";") {
+			args := strings.Fields(strings.ReplaceAll(command, "%i", name))
+			if len(args) == 0 {
+				continue
+			}
+
+			_ = exec.Command(args[0], args[1:]...).Run()
+		}
+	}
+
+	if _, err := exec.LookPath("resolvconf"); err == nil {
+		if err := exec.Command("resolvconf", "-d", fmt.Sprintf("tun.%s", name), "-f").Run(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove the DNS of %s; %s", name, err))
+		}
+	}
+	if _, err := exec.LookPath("nft"); err == nil {
+		for _, family := range []string{"ip", "ip6"} {
+			_ = exec.Command("nft", "delete", "table", family, fmt.Sprintf("wg-quick-%s", name)).Run()
+		}
+	}
+
+	for _, family := range []string{"-4", "-6"} {
+		errs = append(errs, pruneRules(family)...)
+	}
+
+	return errs
+}
+
+// pruneRules deletes the "not fwmark" rules wg-quick adds for a default route
+// once their routing table is empty, which tells them apart from the rules of
+// interfaces that are still up. The shared suppress_prefixlength rule goes
+// with the last of them.
+func pruneRules(family string) []error {
+	output, err := exec.Command("ip", family, "rule", "show").Output()
+	if err != nil {
+		return nil
+	}
+
+	var (
+		errs     []error
+		pruned   bool
+		remain   bool
+		suppress bool
+	)
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if strings.Contains(line, "suppress_prefixlength 0") {
+			suppress = true
+			continue
+		}
+		if fields[1] != "not" || !strings.Contains(line, "fwmark") || fields[len(fields)-2] != "lookup" {
+			continue
+		}
+
+		table := fields[len(fields)-1]
+		routes, err := exec.Command("ip", family, "route", "show", "table", table).Output()
+		if err != nil || len(bytes.TrimSpace(routes)) > 0 {
+			remain = true
+			continue
+		}
+
+		if err := exec.Command("ip", family, "rule", "delete", "table", table).Run(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete the rule for table %s; %s", table, err))
+			remain = true
+			continue
+		}
+
+		pruned = true
+	}
+
+	if pruned && suppress && !remain {
+		if err := exec.Command("ip", family, "rule", "delete", "table", "main", "suppress_prefixlength", "0").Run(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete the suppress_prefixlength rule; %s", err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,7 @@
+package wireguard
+
+// cleanup has nothing to do, as routes and DNS of the tunnel go with its
+// adapter, and the firewall rules are removed by PostDown.
+func (w *WireGuard) cleanup() []error {
+	return nil
+}
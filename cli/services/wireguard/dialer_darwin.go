@@ -0,0 +1,35 @@
+package wireguard
+
+import (
+	"net"
+	"syscall"
+)
+
+func (w *WireGuard) Dialer() (*net.Dialer, error) {
+	name, err := w.RealInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	iFace, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.Dialer{
+		Control: func(network, _ string, c syscall.RawConn) (err error) {
+			if cerr := c.Control(func(fd uintptr) {
+				if network == "tcp6" || network == "udp6" {
+					err = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_BOUND_IF, iFace.Index)
+					return
+				}
+
+				err = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_BOUND_IF, iFace.Index)
+			}); cerr != nil {
+				return cerr
+			}
+
+			return err
+		},
+	}, nil
+}
@@ -0,0 +1,25 @@
+package wireguard
+
+import (
+	"net"
+	"syscall"
+)
+
+func (w *WireGuard) Dialer() (*net.Dialer, error) {
+	iFace, err := w.RealInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.Dialer{
+		Control: func(_, _ string, c syscall.RawConn) (err error) {
+			if cerr := c.Control(func(fd uintptr) {
+				err = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, iFace)
+			}); cerr != nil {
+				return cerr
+			}
+
+			return err
+		},
+	}, nil
+}
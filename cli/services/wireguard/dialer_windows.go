@@ -0,0 +1,45 @@
+package wireguard
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+)
+
+const (
+	ipUnicastIF   = 31
+	ipv6UnicastIF = 31
+)
+
+func (w *WireGuard) Dialer() (*net.Dialer, error) {
+	name, err := w.RealInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	iFace, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.Dialer{
+		Control: func(network, _ string, c syscall.RawConn) (err error) {
+			if cerr := c.Control(func(fd uintptr) {
+				if network == "tcp6" || network == "udp6" {
+					err = syscall.SetsockoptInt(syscall.Handle(fd), syscall.IPPROTO_IPV6, ipv6UnicastIF, iFace.Index)
+					return
+				}
+
+				// IP_UNICAST_IF expects the index in network byte order.
+				var index [4]byte
+				binary.BigEndian.PutUint32(index[:], uint32(iFace.Index))
+				err = syscall.SetsockoptInt(syscall.Handle(fd), syscall.IPPROTO_IP, ipUnicastIF,
+					int(binary.LittleEndian.Uint32(index[:])))
+			}); cerr != nil {
+				return cerr
+			}
+
+			return err
+		},
+	}, nil
+}
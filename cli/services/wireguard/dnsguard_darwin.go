@@ -0,0 +1,124 @@
+package wireguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func (w *WireGuard) dnsBackupPath() string {
+	return filepath.Join(w.cfgDir, fmt.Sprintf("%s.dns", w.cfg.Name))
+}
+
+func networkServices() ([]string, error) {
+	output, err := exec.Command("networksetup", "-listallnetworkservices").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		items []string
+		lines = strings.Split(string(output), "\n")
+	)
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if i == 0 || line == "" || strings.HasPrefix(line, "*") {
+			continue
+		}
+
+		items = append(items, line)
+	}
+
+	return items, nil
+}
+
+// snapshotDNS saves the DNS servers of every network service before wg-quick
+// sets the DNS of the tunnel. A saved one is kept, as it still holds the
+// servers of the user when the interface is brought up again.
+func (w *WireGuard) snapshotDNS() error {
+	path := w.dnsBackupPath()
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	services, err := networkServices()
+	if err != nil {
+		return err
+	}
+
+	backup := make(map[string][]string)
+	for _, service := range services {
+		output, err := exec.Command("networksetup", "-getdnsservers", service).Output()
+		if err != nil {
+			return err
+		}
+
+		servers := []string{"empty"}
+		if !strings.Contains(string(output), " ") {
+			servers = strings.Fields(string(output))
+		}
+
+		backup[service] = servers
+	}
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func (w *WireGuard) enableDNSGuard() error {
+	if _, err := os.Stat(w.dnsBackupPath()); err != nil {
+		return fmt.Errorf("no DNS snapshot to restore from; %s", err)
+	}
+
+	services, err := networkServices()
+	if err != nil {
+		return err
+	}
+
+	dns := make([]string, 0, len(w.cfg.Interface.DNS))
+	for _, item := range w.cfg.Interface.DNS {
+		dns = append(dns, item.String())
+	}
+
+	for _, service := range services {
+		if err := exec.Command("networksetup", append([]string{"-setdnsservers", service}, dns...)...).Run(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *WireGuard) disableDNSGuard() error {
+	path := w.dnsBackupPath()
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var backup map[string][]string
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return err
+	}
+
+	for service, servers := range backup {
+		if err := exec.Command("networksetup", append([]string{"-setdnsservers", service}, servers...)...).Run(); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(path)
+}
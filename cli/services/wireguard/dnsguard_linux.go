@@ -0,0 +1,94 @@
+package wireguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	resolvConfPath = "/etc/resolv.conf"
+)
+
+// dnsBackup is the resolver config as it was before the tunnel came up. Path
+// is the file resolv.conf links to, which is written in place so that a
+// symlink managed by systemd-resolved or resolvconf is kept.
+type dnsBackup struct {
+	Path string `json:"path"`
+	Data []byte `json:"data"`
+}
+
+func (w *WireGuard) dnsBackupPath() string {
+	return filepath.Join(w.cfgDir, fmt.Sprintf("%s.dns", w.cfg.Name))
+}
+
+// snapshotDNS saves the resolver config before wg-quick sets the DNS of the
+// tunnel. A saved one is kept, as it is still the config of the user when the
+// interface is brought up again.
+func (w *WireGuard) snapshotDNS() error {
+	path := w.dnsBackupPath()
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	target, err := filepath.EvalSymlinks(resolvConfPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(target)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(dnsBackup{Path: target, Data: data})
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buf, 0600)
+}
+
+func (w *WireGuard) enableDNSGuard() error {
+	if _, err := os.Stat(w.dnsBackupPath()); err != nil {
+		return fmt.Errorf("no DNS snapshot to restore from; %s", err)
+	}
+
+	target, err := filepath.EvalSymlinks(resolvConfPath)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(w.cfg.Interface.DNS))
+	for _, dns := range w.cfg.Interface.DNS {
+		lines = append(lines, fmt.Sprintf("nameserver %s", dns))
+	}
+
+	return ioutil.WriteFile(target, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+func (w *WireGuard) disableDNSGuard() error {
+	path := w.dnsBackupPath()
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var backup dnsBackup
+	if err := json.Unmarshal(buf, &backup); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(backup.Path, backup.Data, 0644); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
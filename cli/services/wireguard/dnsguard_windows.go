@@ -0,0 +1,75 @@
+package wireguard
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+func excludeIPv4Ranges(ips []net.IP) string {
+	values := make([]uint64, 0, len(ips))
+	for _, ip := range ips {
+		if ip = ip.To4(); ip != nil {
+			values = append(values, uint64(binary.BigEndian.Uint32(ip)))
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	var (
+		ranges []string
+		start  uint64
+	)
+
+	for _, value := range values {
+		if value > start {
+			ranges = append(ranges, fmt.Sprintf("%s-%s", uint32ToIP(uint32(start)), uint32ToIP(uint32(value-1))))
+		}
+
+		start = value + 1
+	}
+
+	if start <= math.MaxUint32 {
+		ranges = append(ranges, fmt.Sprintf("%s-%s", uint32ToIP(uint32(start)), uint32ToIP(math.MaxUint32)))
+	}
+
+	return strings.Join(ranges, ",")
+}
+
+func uint32ToIP(v uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, v)
+
+	return ip
+}
+
+func (w *WireGuard) enableDNSGuard() error {
+	var (
+		name     = fmt.Sprintf("name=%s", types.DNSGuardTag)
+		remoteIP = fmt.Sprintf("remoteip=%s", excludeIPv4Ranges(w.cfg.Interface.DNS))
+	)
+
+	for _, protocol := range []string{"udp", "tcp"} {
+		if err := netsh("firewall", "add", "rule", name, "dir=out", "action=block",
+			fmt.Sprintf("protocol=%s", protocol), "remoteport=53", remoteIP); err != nil {
+			_ = w.disableDNSGuard()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *WireGuard) disableDNSGuard() error {
+	name := fmt.Sprintf("name=%s", types.DNSGuardTag)
+	if err := netsh("firewall", "show", "rule", name); err != nil {
+		return nil
+	}
+
+	return netsh("firewall", "delete", "rule", name)
+}
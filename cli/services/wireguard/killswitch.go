@@ -0,0 +1,48 @@
+package wireguard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+// killSwitchStatePath is where the kill switch keeps what it changed besides
+// its own rules, so that it is undone by FlushKillSwitch even after a crash.
+func killSwitchStatePath(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.json", types.KillSwitchTag))
+}
+
+func saveKillSwitchState(dir string, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(killSwitchStatePath(dir), buf, 0600)
+}
+
+// loadKillSwitchState reads the kept state into v, and tells whether there
+// is one.
+func loadKillSwitchState(dir string, v interface{}) (bool, error) {
+	buf, err := ioutil.ReadFile(killSwitchStatePath(dir))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(buf, v)
+}
+
+func removeKillSwitchState(dir string) error {
+	if err := os.Remove(killSwitchStatePath(dir)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
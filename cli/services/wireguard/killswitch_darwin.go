@@ -0,0 +1,104 @@
+package wireguard
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+var (
+	pfTokenRegexp = regexp.MustCompile(`Token : (\d+)`)
+)
+
+// pfState is the reference pfctl -E took on pf, which keeps pf enabled until
+// it is released, whoever else enabled it.
+type pfState struct {
+	Token string `json:"token"`
+}
+
+// enableKillSwitch loads the rules into an anchor of their own and enables pf
+// with a reference, which is kept so that FlushKillSwitch releases just that
+// one. What a crash left behind is flushed first.
+func (w *WireGuard) enableKillSwitch() error {
+	if err := FlushKillSwitch(w.cfgDir); err != nil {
+		return err
+	}
+
+	iFace, err := w.RealInterface()
+	if err != nil {
+		return err
+	}
+
+	rules := []string{
+		"block drop out all",
+		"pass out quick on lo0 all",
+		fmt.Sprintf("pass out quick on %s all", iFace),
+	}
+	for _, peer := range w.cfg.Peers {
+		rules = append(rules,
+			fmt.Sprintf("pass out quick proto udp to %s port %d", peer.Endpoint.Host, peer.Endpoint.Port))
+	}
+
+	// The node API is reached outside the tunnel when reconnecting.
+	if w.nodeAPI.Host != "" {
+		rules = append(rules,
+			fmt.Sprintf("pass out quick proto tcp to %s port %d", w.nodeAPI.Host, w.nodeAPI.Port))
+	}
+
+	cmd := exec.Command("pfctl", "-a", fmt.Sprintf("com.apple/%s", types.KillSwitchTag), "-f", "-")
+	cmd.Stdin = strings.NewReader(strings.Join(rules, "\n") + "\n")
+	if err := cmd.Run(); err != nil {
+		_ = FlushKillSwitch(w.cfgDir)
+		return err
+	}
+
+	output, err := exec.Command("pfctl", "-E").CombinedOutput()
+	if err != nil {
+		_ = FlushKillSwitch(w.cfgDir)
+		return err
+	}
+
+	match := pfTokenRegexp.FindSubmatch(output)
+	if match == nil {
+		_ = FlushKillSwitch(w.cfgDir)
+		return fmt.Errorf("no pf reference token in the output of pfctl -E")
+	}
+	if err := saveKillSwitchState(w.cfgDir, pfState{Token: string(match[1])}); err != nil {
+		_ = exec.Command("pfctl", "-X", string(match[1])).Run()
+		_ = FlushKillSwitch(w.cfgDir)
+		return err
+	}
+
+	return nil
+}
+
+// FlushKillSwitch flushes the anchor of the kill switch and releases the pf
+// reference kept in dir when it was enabled.
+func FlushKillSwitch(dir string) error {
+	var errs []error
+	if err := exec.Command("pfctl", "-a", fmt.Sprintf("com.apple/%s", types.KillSwitchTag), "-F", "all").Run(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush the kill switch anchor; %s", err))
+	}
+
+	var state pfState
+	ok, err := loadKillSwitchState(dir, &state)
+	if err != nil {
+		return utils.JoinErrors(append(errs, err)...)
+	}
+	if !ok {
+		return utils.JoinErrors(errs...)
+	}
+
+	// A token is only rejected once it is no longer held, as none outlives a
+	// reboot, so the state goes either way.
+	_ = exec.Command("pfctl", "-X", state.Token).Run()
+	if err := removeKillSwitchState(dir); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utils.JoinErrors(errs...)
+}
@@ -0,0 +1,103 @@
+package wireguard
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+func iptables(bin, args string) error {
+	return exec.Command(bin, strings.Split(args, " ")...).Run()
+}
+
+// enableKillSwitch adds the rules to a chain of their own, jumped to from
+// OUTPUT, so that they are removed without touching any other rule. What a
+// crash left behind is flushed first, as the chain cannot be created twice.
+func (w *WireGuard) enableKillSwitch() error {
+	if err := FlushKillSwitch(w.cfgDir); err != nil {
+		return err
+	}
+
+	var (
+		chain  = types.KillSwitchTag
+		iFace  = w.cfg.Name
+		rules4 = []string{
+			fmt.Sprintf("-N %s", chain),
+			fmt.Sprintf("-A %s -o lo -j ACCEPT", chain),
+			fmt.Sprintf("-A %s -o %s -j ACCEPT", chain, iFace),
+		}
+		rules6 = []string{
+			fmt.Sprintf("-N %s", chain),
+			fmt.Sprintf("-A %s -o lo -j ACCEPT", chain),
+			fmt.Sprintf("-A %s -o %s -j ACCEPT", chain, iFace),
+		}
+	)
+
+	for _, peer := range w.cfg.Peers {
+		rule := fmt.Sprintf("-A %s -d %s -p udp --dport %d -j ACCEPT", chain, peer.Endpoint.Host, peer.Endpoint.Port)
+		if strings.Contains(peer.Endpoint.Host, ":") {
+			rules6 = append(rules6, rule)
+		} else {
+			rules4 = append(rules4, rule)
+		}
+	}
+
+	// The node API is reached outside the tunnel when reconnecting.
+	if w.nodeAPI.Host != "" {
+		rule := fmt.Sprintf("-A %s -d %s -p tcp --dport %d -j ACCEPT", chain, w.nodeAPI.Host, w.nodeAPI.Port)
+		if strings.Contains(w.nodeAPI.Host, ":") {
+			rules6 = append(rules6, rule)
+		} else {
+			rules4 = append(rules4, rule)
+		}
+	}
+
+	rules4 = append(rules4, fmt.Sprintf("-A %s -j REJECT", chain), fmt.Sprintf("-I OUTPUT -j %s", chain))
+	rules6 = append(rules6, fmt.Sprintf("-A %s -j REJECT", chain), fmt.Sprintf("-I OUTPUT -j %s", chain))
+
+	for _, rule := range rules4 {
+		if err := iptables("iptables", rule); err != nil {
+			_ = FlushKillSwitch(w.cfgDir)
+			return err
+		}
+	}
+	for _, rule := range rules6 {
+		if err := iptables("ip6tables", rule); err != nil {
+			_ = FlushKillSwitch(w.cfgDir)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FlushKillSwitch removes every jump to the kill switch chain, then the chain
+// itself. The kill switch keeps no state on Linux, so dir is not used.
+func FlushKillSwitch(_ string) error {
+	var (
+		chain = types.KillSwitchTag
+		errs  []error
+	)
+
+	for _, bin := range []string{"iptables", "ip6tables"} {
+		if err := iptables(bin, fmt.Sprintf("-n -L %s", chain)); err != nil {
+			continue
+		}
+
+		for iptables(bin, fmt.Sprintf("-D OUTPUT -j %s", chain)) == nil {
+		}
+
+		if err := iptables(bin, fmt.Sprintf("-F %s", chain)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush the %s chain %s; %s", bin, chain, err))
+			continue
+		}
+		if err := iptables(bin, fmt.Sprintf("-X %s", chain)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete the %s chain %s; %s", bin, chain, err))
+		}
+	}
+
+	return utils.JoinErrors(errs...)
+}
@@ -0,0 +1,37 @@
+package wireguard
+
+import (
+	"testing"
+)
+
+func TestKillSwitchState(t *testing.T) {
+	dir := t.TempDir()
+
+	var v map[string]string
+	if ok, err := loadKillSwitchState(dir, &v); err != nil || ok {
+		t.Fatalf("expected no state, got %v and %v", ok, err)
+	}
+
+	saved := map[string]string{"publicprofile": "BlockInbound,AllowOutbound"}
+	if err := saveKillSwitchState(dir, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := loadKillSwitchState(dir, &v)
+	if err != nil || !ok {
+		t.Fatalf("expected the saved state, got %v and %v", ok, err)
+	}
+	if v["publicprofile"] != saved["publicprofile"] {
+		t.Fatalf("expected %v, got %v", saved, v)
+	}
+
+	if err := removeKillSwitchState(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeKillSwitchState(dir); err != nil {
+		t.Fatalf("expected removing a missing state to succeed, got %v", err)
+	}
+	if ok, err := loadKillSwitchState(dir, &v); err != nil || ok {
+		t.Fatalf("expected no state after removing it, got %v and %v", ok, err)
+	}
+}
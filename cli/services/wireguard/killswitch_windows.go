@@ -0,0 +1,137 @@
+package wireguard
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+var (
+	firewallProfiles = []string{"domainprofile", "privateprofile", "publicprofile"}
+
+	// The policy values are not translated, unlike the rest of the output.
+	firewallPolicyRegexp = regexp.MustCompile(`(?i)^(BlockInboundAlways|BlockInbound|AllowInbound|NotConfigured),(AllowOutbound|BlockOutbound|NotConfigured)$`)
+)
+
+func netsh(args ...string) error {
+	return exec.Command("netsh", append([]string{"advfirewall"}, args...)...).Run()
+}
+
+// firewallPolicy returns the inbound and outbound policy of the profile.
+func firewallPolicy(profile string) (string, error) {
+	output, err := exec.Command("netsh", "advfirewall", "show", profile, "firewallpolicy").Output()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && firewallPolicyRegexp.MatchString(fields[len(fields)-1]) {
+			return fields[len(fields)-1], nil
+		}
+	}
+
+	return "", fmt.Errorf("no firewall policy found for %s", profile)
+}
+
+// enableKillSwitch saves the firewall policy of each profile before blocking
+// the outbound traffic, so that FlushKillSwitch restores what the user had.
+// What a crash left behind is flushed first, restoring the policy saved then.
+func (w *WireGuard) enableKillSwitch() error {
+	if err := FlushKillSwitch(w.cfgDir); err != nil {
+		return err
+	}
+
+	policies := make(map[string]string)
+	for _, profile := range firewallProfiles {
+		policy, err := firewallPolicy(profile)
+		if err != nil {
+			return err
+		}
+
+		policies[profile] = policy
+	}
+
+	if err := saveKillSwitchState(w.cfgDir, policies); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("name=%s", types.KillSwitchTag)
+	for _, address := range w.cfg.Interface.Addresses {
+		if err := netsh("firewall", "add", "rule", name, "dir=out", "action=allow",
+			fmt.Sprintf("localip=%s", address.IP)); err != nil {
+			_ = FlushKillSwitch(w.cfgDir)
+			return err
+		}
+	}
+	for _, peer := range w.cfg.Peers {
+		if err := netsh("firewall", "add", "rule", name, "dir=out", "action=allow", "protocol=udp",
+			fmt.Sprintf("remoteip=%s", peer.Endpoint.Host), fmt.Sprintf("remoteport=%d", peer.Endpoint.Port)); err != nil {
+			_ = FlushKillSwitch(w.cfgDir)
+			return err
+		}
+	}
+
+	// The node API is reached outside the tunnel when reconnecting.
+	if w.nodeAPI.Host != "" {
+		if err := netsh("firewall", "add", "rule", name, "dir=out", "action=allow", "protocol=tcp",
+			fmt.Sprintf("remoteip=%s", w.nodeAPI.Host), fmt.Sprintf("remoteport=%d", w.nodeAPI.Port)); err != nil {
+			_ = FlushKillSwitch(w.cfgDir)
+			return err
+		}
+	}
+
+	for _, profile := range firewallProfiles {
+		inbound := strings.SplitN(policies[profile], ",", 2)[0]
+		if err := netsh("set", profile, "firewallpolicy", inbound+",blockoutbound"); err != nil {
+			_ = FlushKillSwitch(w.cfgDir)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FlushKillSwitch deletes the rules of the kill switch and restores the
+// firewall policy saved in dir when it was enabled.
+func FlushKillSwitch(dir string) error {
+	var errs []error
+
+	name := fmt.Sprintf("name=%s", types.KillSwitchTag)
+	if err := netsh("firewall", "show", "rule", name); err == nil {
+		if err := netsh("firewall", "delete", "rule", name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete the kill switch rules; %s", err))
+		}
+	}
+
+	policies := make(map[string]string)
+	ok, err := loadKillSwitchState(dir, &policies)
+	if err != nil {
+		return utils.JoinErrors(append(errs, err)...)
+	}
+	if !ok {
+		return utils.JoinErrors(errs...)
+	}
+
+	restored := true
+	for profile, policy := range policies {
+		if err := netsh("set", profile, "firewallpolicy", policy); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore the firewall policy of %s; %s", profile, err))
+			restored = false
+		}
+	}
+
+	// The state is kept until the policy is restored, so that a later flush
+	// tries again.
+	if restored {
+		if err := removeKillSwitchState(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utils.JoinErrors(errs...)
+}
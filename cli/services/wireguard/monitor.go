@@ -0,0 +1,206 @@
+package wireguard
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+// ReconnectFunc requests a new session from the node and returns the config
+// the interface should be brought up with.
+type ReconnectFunc func(c context.Context) (*types.Config, error)
+
+type MonitorOptions struct {
+	Interval  time.Duration
+	Threshold time.Duration
+	Attempts  uint64
+	Backoff   time.Duration
+}
+
+func (w *WireGuard) Subscribe() (<-chan types.Event, func()) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	events := make(chan types.Event, 8)
+	if w.subscribers == nil {
+		w.subscribers = make(map[chan types.Event]struct{})
+	}
+
+	w.subscribers[events] = struct{}{}
+	return events, func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+
+		delete(w.subscribers, events)
+	}
+}
+
+func (w *WireGuard) Emit(event types.Event) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for events := range w.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// StartMonitor checks the latest handshake every interval and reconnects with
+// the func set by WithReconnect once it is older than the threshold. A
+// missing handshake counts as stale once the threshold has passed since the
+// monitor started or last reconnected. Without such a func it does nothing.
+func (w *WireGuard) StartMonitor(opts MonitorOptions) {
+	w.stopMonitor()
+	if w.reconnectFn == nil {
+		return
+	}
+
+	c, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	w.mutex.Lock()
+	w.monitorCancel, w.monitorDone = cancel, done
+	w.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		since := time.Now()
+		for {
+			select {
+			case <-c.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// A failed reconnect leaves the interface down, in which case it
+			// is tried again without waiting for the threshold.
+			handshake, err := w.LatestHandshake()
+			switch {
+			case err == nil:
+				if handshake.After(since) {
+					since = handshake
+				}
+				if time.Since(since) < opts.Threshold {
+					continue
+				}
+			case w.IsUp():
+				log.Printf("Failed to read the latest handshake of %s: %s", w.Config().Name, err)
+				continue
+			}
+
+			w.Emit(types.NewEvent(types.EventHandshakeStale))
+			if w.reconnect(c, opts) {
+				since = time.Now()
+			}
+		}
+	}()
+}
+
+// reconnect takes the interface down before asking the node for a new
+// session, as the request would otherwise go through the tunnel that has
+// failed. With the kill switch on, the node API is let through by its
+// exception.
+func (w *WireGuard) reconnect(c context.Context, opts MonitorOptions) bool {
+	w.reconfigMutex.Lock()
+	err := w.down()
+	w.reconfigMutex.Unlock()
+	if err != nil {
+		w.Emit(types.NewEvent(types.EventReconnectFailed).WithError(err))
+		return false
+	}
+
+	backoff := opts.Backoff
+	for attempt := uint64(1); attempt <= opts.Attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-c.Done():
+				return false
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+		}
+
+		cfg, err := w.reconnectFn(c)
+		if err == nil {
+			err = w.reconfigure(cfg)
+		}
+		if c.Err() != nil {
+			return false
+		}
+		if err != nil {
+			w.Emit(types.NewEvent(types.EventReconnectFailed).WithAttempt(attempt).WithError(err))
+			continue
+		}
+
+		w.Emit(types.NewEvent(types.EventReconnected).WithAttempt(attempt))
+		return true
+	}
+
+	return false
+}
+
+func (w *WireGuard) reconfigure(cfg *types.Config) error {
+	if err := checkPeers(cfg); err != nil {
+		return err
+	}
+
+	w.reconfigMutex.Lock()
+	defer w.reconfigMutex.Unlock()
+
+	return w.restart(cfg)
+}
+
+func (w *WireGuard) restart(cfg *types.Config) error {
+	if err := w.down(); err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	w.cfg = cfg
+	w.mutex.Unlock()
+
+	if err := w.PreUp(); err != nil {
+		return err
+	}
+	if err := w.Up(); err != nil {
+		return err
+	}
+	if err := w.refreshKillSwitch(); err != nil {
+		return err
+	}
+
+	cfg.Interface.PrivateKey.Zero()
+	return nil
+}
+
+// down takes the interface down unless it is already.
+func (w *WireGuard) down() error {
+	if !w.IsUp() {
+		return nil
+	}
+
+	return w.Down()
+}
+
+func (w *WireGuard) stopMonitor() {
+	w.mutex.Lock()
+	cancel, done := w.monitorCancel, w.monitorDone
+	w.monitorCancel, w.monitorDone = nil, nil
+	w.mutex.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
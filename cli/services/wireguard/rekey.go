@@ -0,0 +1,143 @@
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+func equalIPNets(a, b []types.IPNet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Net != b[i].Net || !a[i].IP.Equal(b[i].IP) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalEndpoints tells whether the peers have the same endpoints in turn, as
+// the kill switch lets through the endpoint of every hop.
+func equalEndpoints(a, b []types.Peer) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Endpoint != b[i].Endpoint {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkPeers checks that the config of a new session has a peer to connect
+// to, before the running one is replaced with it.
+func checkPeers(cfg *types.Config) error {
+	if len(cfg.Peers) == 0 {
+		return fmt.Errorf("new session config has no peers")
+	}
+
+	return nil
+}
+
+// refreshKillSwitch replaces the rules of the kill switch with the ones of
+// the current config, as enableKillSwitch flushes the old ones first.
+func (w *WireGuard) refreshKillSwitch() error {
+	if !w.killSwitch {
+		return nil
+	}
+
+	return w.enableKillSwitch()
+}
+
+// Rekey requests a new session with a fresh key and applies it. When the
+// interface addresses stay the same, the key and peers are swapped in place
+// with wg syncconf so the tunnel is not torn down; otherwise the interface
+// is restarted with the new config.
+func (w *WireGuard) Rekey(c context.Context) (bool, error) {
+	if w.reconnectFn == nil {
+		return false, fmt.Errorf("session does not support rekeying")
+	}
+
+	cfg, err := w.reconnectFn(c)
+	if err != nil {
+		return false, err
+	}
+	if err := checkPeers(cfg); err != nil {
+		return false, err
+	}
+
+	w.reconfigMutex.Lock()
+	defer w.reconfigMutex.Unlock()
+
+	prev := w.Config()
+	if !equalIPNets(prev.Interface.Addresses, cfg.Interface.Addresses) {
+		return true, w.restart(cfg)
+	}
+
+	cfg.Interface.ListenPort = prev.Interface.ListenPort
+
+	w.mutex.Lock()
+	w.cfg = cfg
+	w.mutex.Unlock()
+
+	if err := w.PreUp(); err != nil {
+		return false, err
+	}
+	if err := w.syncConf(); err != nil {
+		return false, err
+	}
+	if !equalEndpoints(prev.Peers, cfg.Peers) {
+		if err := w.refreshKillSwitch(); err != nil {
+			return false, err
+		}
+	}
+
+	cfg.Interface.PrivateKey.Zero()
+	return false, nil
+}
+
+func (w *WireGuard) syncConf() error {
+	iFace, err := w.RealInterface()
+	if err != nil {
+		return err
+	}
+
+	stripped, err := exec.Command("wg-quick", "strip",
+		filepath.Join(w.cfgDir, fmt.Sprintf("%s.conf", w.cfg.Name))).Output()
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(w.cfgDir, "syncconf-*.conf")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	if _, err := file.Write(stripped); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("wg", "syncconf", iFace, file.Name())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,55 @@
+package wireguard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+func TestEqualEndpoints(t *testing.T) {
+	var (
+		a = types.Endpoint{Host: "198.51.100.1", Port: 51820}
+		b = types.Endpoint{Host: "198.51.100.2", Port: 51820}
+		c = types.Endpoint{Host: "198.51.100.3", Port: 51820}
+	)
+
+	tests := []struct {
+		name  string
+		x, y  []types.Peer
+		equal bool
+	}{
+		{"same", []types.Peer{{Endpoint: a}, {Endpoint: b}}, []types.Peer{{Endpoint: a}, {Endpoint: b}}, true},
+		{"later hop changed", []types.Peer{{Endpoint: a}, {Endpoint: b}}, []types.Peer{{Endpoint: a}, {Endpoint: c}}, false},
+		{"entry changed", []types.Peer{{Endpoint: a}}, []types.Peer{{Endpoint: b}}, false},
+		{"hop added", []types.Peer{{Endpoint: a}}, []types.Peer{{Endpoint: a}, {Endpoint: b}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := equalEndpoints(tt.x, tt.y); got != tt.equal {
+				t.Fatalf("expected %v, got %v", tt.equal, got)
+			}
+		})
+	}
+}
+
+func TestRekeyWithoutPeers(t *testing.T) {
+	prev := &types.Config{
+		Name:  "wg0",
+		Peers: []types.Peer{{Endpoint: types.Endpoint{Host: "198.51.100.1", Port: 51820}}},
+	}
+
+	w := NewWireGuard().
+		WithConfig(prev).
+		WithReconnect(func(context.Context) (*types.Config, error) {
+			return &types.Config{Name: "wg0"}, nil
+		})
+
+	if _, err := w.Rekey(context.Background()); err == nil {
+		t.Fatal("expected an error for a config without peers")
+	}
+	if w.Config() != prev {
+		t.Fatal("expected the running config to be kept")
+	}
+}
@@ -0,0 +1,55 @@
+package wireguard
+
+import (
+	"fmt"
+
+	"github.com/sentinel-official/desktop-client/cli/utils"
+)
+
+// Stop brings the interface down and rolls back everything the session
+// changed on the host. Unlike PreDown, Down and PostDown in turn, it carries on
+// past a failed step and returns every failure together. It fails if the
+// interface still exists afterwards, keeping the config file so that stopping
+// can be retried.
+func (w *WireGuard) Stop() error {
+	var errs []error
+	if err := w.PreDown(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop the monitor; %s", err))
+	}
+
+	// wg-quick down undoes its own changes, so the host only needs repairing
+	// when it did not run or failed part way.
+	repair := true
+	if w.IsUp() {
+		err := w.Down()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to bring the interface down; %s", err))
+		}
+		if w.IsUp() {
+			errs = append(errs, fmt.Errorf("interface %s still exists", w.cfg.Name))
+			return utils.JoinErrors(errs...)
+		}
+
+		repair = err != nil
+	}
+
+	if repair {
+		errs = append(errs, w.cleanup()...)
+	}
+
+	errs = append(errs, w.postDown()...)
+	return utils.JoinErrors(errs...)
+}
+
+// Cleanup repairs the host after a session whose interface is already gone,
+// as after a crash between Up and Down, undoing what wg-quick down and
+// PostDown would have. It is safe to run more than once.
+func (w *WireGuard) Cleanup() error {
+	if w.IsUp() {
+		return fmt.Errorf("interface %s is up", w.cfg.Name)
+	}
+
+	errs := w.cleanup()
+	errs = append(errs, w.postDown()...)
+	return utils.JoinErrors(errs...)
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -109,6 +110,21 @@ func (c *Config) ToWgQuick() string {
 	return output.String()
 }
 
+// ToWgQuickRedacted renders the config like ToWgQuick with the private and
+// preshared keys replaced, so it can be shown to the user.
+func (c *Config) ToWgQuickRedacted() string {
+	lines := strings.Split(c.ToWgQuick(), "\n")
+	for i, line := range lines {
+		for _, field := range []string{"PrivateKey", "PresharedKey"} {
+			if strings.HasPrefix(line, field+" = ") {
+				lines[i] = field + " = <redacted>"
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func (c *Config) WriteToFile(dir string) error {
 	return ioutil.WriteFile(
 		filepath.Join(dir, fmt.Sprintf("%s.conf", c.Name)),
@@ -116,3 +132,215 @@ func (c *Config) WriteToFile(dir string) error {
 		0600,
 	)
 }
+
+func (c *Config) MarshalINI() ([]byte, error) {
+	return []byte(c.ToWgQuick()), nil
+}
+
+func parseAddress(s string) (*IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	ones, _ := ipNet.Mask.Size()
+	return &IPNet{
+		IP:  ip,
+		Net: uint8(ones),
+	}, nil
+}
+
+func parseAddresses(s string) ([]IPNet, error) {
+	var items []IPNet
+	for _, item := range strings.Split(s, ",") {
+		address, err := parseAddress(item)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, *address)
+	}
+
+	return items, nil
+}
+
+func parseEndpoint(s string) (*Endpoint, error) {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Endpoint{
+		Host: host,
+		Port: uint16(v),
+	}, nil
+}
+
+// ParseConfig reads a wg-quick config as written by ToWgQuick. The name is
+// not part of the file and is left empty, and keys the Config has no field
+// for, such as Table or FwMark, are skipped.
+func ParseConfig(data []byte) (*Config, error) {
+	var (
+		cfg     = &Config{}
+		section = ""
+		peer    *Peer
+	)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section = line
+			if section == "[Peer]" {
+				cfg.Peers = append(cfg.Peers, Peer{})
+				peer = &cfg.Peers[len(cfg.Peers)-1]
+			}
+
+			continue
+		}
+
+		items := strings.SplitN(line, "=", 2)
+		if len(items) != 2 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+
+		var (
+			err        error
+			key, value = strings.TrimSpace(items[0]), strings.TrimSpace(items[1])
+		)
+
+		switch section {
+		case "[Interface]":
+			err = cfg.Interface.set(key, value)
+		case "[Peer]":
+			err = peer.set(key, value)
+		default:
+			err = fmt.Errorf("unexpected key %s outside a section", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func (i *Interface) set(key, value string) error {
+	switch key {
+	case "PrivateKey":
+		k, err := NewKeyFromString(value)
+		if err != nil {
+			return err
+		}
+
+		i.PrivateKey = *k
+	case "Address":
+		addresses, err := parseAddresses(value)
+		if err != nil {
+			return err
+		}
+
+		i.Addresses = append(i.Addresses, addresses...)
+	case "ListenPort":
+		port, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return err
+		}
+
+		i.ListenPort = uint16(port)
+	case "MTU":
+		mtu, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return err
+		}
+
+		i.MTU = uint16(mtu)
+	case "DNS":
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if ip := net.ParseIP(item); ip != nil {
+				if v4 := ip.To4(); v4 != nil {
+					ip = v4
+				}
+
+				i.DNS = append(i.DNS, ip)
+			} else {
+				i.DNSSearch = append(i.DNSSearch, item)
+			}
+		}
+	case "PreUp":
+		i.PreUp = value
+	case "PostUp":
+		i.PostUp = value
+	case "PreDown":
+		i.PreDown = value
+	case "PostDown":
+		i.PostDown = value
+	}
+
+	return nil
+}
+
+func (p *Peer) set(key, value string) error {
+	switch key {
+	case "PublicKey", "PresharedKey":
+		k, err := NewKeyFromString(value)
+		if err != nil {
+			return err
+		}
+
+		if key == "PublicKey" {
+			p.PublicKey = *k
+		} else {
+			p.PresharedKey = *k
+		}
+	case "AllowedIPs":
+		addresses, err := parseAddresses(value)
+		if err != nil {
+			return err
+		}
+
+		p.AllowedIPs = append(p.AllowedIPs, addresses...)
+	case "Endpoint":
+		endpoint, err := parseEndpoint(value)
+		if err != nil {
+			return err
+		}
+
+		p.Endpoint = *endpoint
+	case "PersistentKeepalive":
+		keepalive, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return err
+		}
+
+		p.PersistentKeepalive = uint16(keepalive)
+	}
+
+	return nil
+}
+
+func NewConfigFromFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return cfg, nil
+}
@@ -0,0 +1,176 @@
+package types
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) Key {
+	return *NewKey(bytes.Repeat([]byte{b}, KeyLength))
+}
+
+func testIPNet(ip string, n uint8) IPNet {
+	v := net.ParseIP(ip)
+	if v4 := v.To4(); v4 != nil {
+		v = v4
+	}
+
+	return IPNet{IP: v, Net: n}
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			"single peer without DNS",
+			&Config{
+				Interface: Interface{
+					PrivateKey: testKey(1),
+					Addresses:  []IPNet{testIPNet("10.8.0.2", 32)},
+				},
+				Peers: []Peer{
+					{
+						PublicKey:  testKey(2),
+						AllowedIPs: []IPNet{testIPNet("0.0.0.0", 0)},
+						Endpoint:   Endpoint{Host: "203.0.113.1", Port: 51820},
+					},
+				},
+			},
+		},
+		{
+			"multiple peers with IPv6",
+			&Config{
+				Interface: Interface{
+					PrivateKey: testKey(1),
+					Addresses:  []IPNet{testIPNet("10.8.0.2", 32), testIPNet("fd86:ea04:1115::2", 128)},
+					ListenPort: 51821,
+					MTU:        1420,
+					DNS:        []net.IP{net.ParseIP("10.8.0.1").To4(), net.ParseIP("fd86:ea04:1115::1")},
+					DNSSearch:  []string{"example.com"},
+					PostUp:     "echo up",
+					PreDown:    "echo down",
+				},
+				Peers: []Peer{
+					{
+						PublicKey:           testKey(2),
+						PresharedKey:        testKey(3),
+						AllowedIPs:          []IPNet{testIPNet("0.0.0.0", 1), testIPNet("128.0.0.0", 1), testIPNet("198.51.100.2", 32)},
+						Endpoint:            Endpoint{Host: "198.51.100.1", Port: 51820},
+						PersistentKeepalive: 15,
+					},
+					{
+						PublicKey:  testKey(4),
+						AllowedIPs: []IPNet{testIPNet("::", 1), testIPNet("8000::", 1)},
+						Endpoint:   Endpoint{Host: "2001:db8::1", Port: 51820},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.cfg.MarshalINI()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := ParseConfig(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(parsed, tt.cfg) {
+				t.Fatalf("expected\n%+v\ngot\n%+v", tt.cfg, parsed)
+			}
+		})
+	}
+}
+
+func TestParseConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"key outside a section", "PrivateKey = x\n"},
+		{"line without a value", "[Interface]\nPrivateKey\n"},
+		{"invalid key", "[Interface]\nPrivateKey = not-a-key\n"},
+		{"invalid address", "[Interface]\nAddress = 10.8.0.2\n"},
+		{"invalid port", "[Interface]\nListenPort = 70000\n"},
+		{"invalid endpoint", "[Peer]\nEndpoint = 2001:db8::1:51820\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(tt.data)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestConfigDNSMixedFamilies(t *testing.T) {
+	tests := []struct {
+		name   string
+		dns    []string
+		search []string
+		line   string
+	}{
+		{"IPv4 only", []string{"10.8.0.1"}, nil, "DNS = 10.8.0.1"},
+		{"IPv6 only", []string{"fd86:ea04:1115::1"}, nil, "DNS = fd86:ea04:1115::1"},
+		{"mixed", []string{"10.8.0.1", "2606:4700:4700::1111", "1.1.1.1"}, nil,
+			"DNS = 10.8.0.1, 2606:4700:4700::1111, 1.1.1.1"},
+		{"mixed with search domain", []string{"2606:4700:4700::1111", "10.8.0.1"}, []string{"example.com"},
+			"DNS = 2606:4700:4700::1111, 10.8.0.1, example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Name: "wg0"}
+			for _, item := range tt.dns {
+				cfg.Interface.DNS = append(cfg.Interface.DNS, net.ParseIP(item))
+			}
+			cfg.Interface.DNSSearch = tt.search
+
+			output := cfg.ToWgQuick()
+			if !strings.Contains(output, tt.line+"\n") {
+				t.Fatalf("expected line %q in\n%s", tt.line, output)
+			}
+
+			parsed, err := ParseConfig([]byte(output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(parsed.Interface.DNS) != len(tt.dns) {
+				t.Fatalf("expected %d resolvers after parsing, got %d", len(tt.dns), len(parsed.Interface.DNS))
+			}
+			for i := range tt.dns {
+				if !parsed.Interface.DNS[i].Equal(net.ParseIP(tt.dns[i])) {
+					t.Errorf("expected resolver %s at %d, got %s", tt.dns[i], i, parsed.Interface.DNS[i])
+				}
+			}
+			if strings.Join(parsed.Interface.DNSSearch, ",") != strings.Join(tt.search, ",") {
+				t.Errorf("expected search domains %v, got %v", tt.search, parsed.Interface.DNSSearch)
+			}
+		})
+	}
+}
+
+func TestConfigDefaultRoutes(t *testing.T) {
+	cfg := &Config{
+		Peers: []Peer{
+			{AllowedIPs: []IPNet{DefaultRouteIPv4, DefaultRouteIPv6}},
+		},
+	}
+
+	if line := "AllowedIPs = 0.0.0.0/0, ::/0\n"; !strings.Contains(cfg.ToWgQuick(), line) {
+		t.Fatalf("expected line %q in\n%s", line, cfg.ToWgQuick())
+	}
+	if n := len(DefaultRouteIPv4.IP); n != net.IPv4len {
+		t.Fatalf("expected a %d byte IPv4 default route, got %d bytes", net.IPv4len, n)
+	}
+}
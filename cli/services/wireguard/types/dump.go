@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PeerStats struct {
+	PublicKey           string
+	Endpoint            string
+	AllowedIPs          []string
+	LatestHandshake     time.Time
+	Download            int64
+	Upload              int64
+	PersistentKeepalive uint64
+}
+
+type Dump struct {
+	PublicKey  string
+	ListenPort uint16
+	Peers      []PeerStats
+}
+
+func none(v string) string {
+	if v == "(none)" {
+		return ""
+	}
+
+	return v
+}
+
+// NewDumpFromOutput parses the output of wg show <interface> dump. The first
+// line describes the interface and every following line describes a peer.
+func NewDumpFromOutput(output string) (*Dump, error) {
+	var dump Dump
+	for i, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		columns := strings.Split(line, "\t")
+		if i == 0 {
+			if len(columns) != 4 {
+				return nil, fmt.Errorf("invalid dump interface line; expected 4 columns")
+			}
+
+			port, err := strconv.ParseUint(columns[2], 10, 16)
+			if err != nil {
+				return nil, err
+			}
+
+			dump.PublicKey, dump.ListenPort = none(columns[1]), uint16(port)
+			continue
+		}
+		if len(columns) != 8 {
+			return nil, fmt.Errorf("invalid dump peer line; expected 8 columns")
+		}
+
+		peer := PeerStats{
+			PublicKey: columns[0],
+			Endpoint:  none(columns[2]),
+		}
+		if v := none(columns[3]); v != "" {
+			peer.AllowedIPs = strings.Split(v, ",")
+		}
+
+		seconds, err := strconv.ParseInt(columns[4], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if seconds != 0 {
+			peer.LatestHandshake = time.Unix(seconds, 0)
+		}
+
+		peer.Download, err = strconv.ParseInt(columns[5], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		peer.Upload, err = strconv.ParseInt(columns[6], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		if columns[7] != "off" {
+			peer.PersistentKeepalive, err = strconv.ParseUint(columns[7], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		dump.Peers = append(dump.Peers, peer)
+	}
+
+	return &dump, nil
+}
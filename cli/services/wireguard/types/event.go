@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	EventHandshakeStale  = "handshake_stale"
+	EventReconnected     = "reconnected"
+	EventReconnectFailed = "reconnect_failed"
+	EventQuotaExceeded   = "quota_exceeded"
+	EventSessionExpired  = "session_expired"
+)
+
+type Event struct {
+	Type    string    `json:"type"`
+	Attempt uint64    `json:"attempt,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+func NewEvent(t string) Event {
+	return Event{
+		Type: t,
+		Time: time.Now(),
+	}
+}
+
+func (e Event) WithAttempt(v uint64) Event { e.Attempt = v; return e }
+func (e Event) WithError(v error) Event    { e.Error = v.Error(); return e }
+func (e Event) WithReason(v string) Event  { e.Reason = v; return e }
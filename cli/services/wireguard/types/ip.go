@@ -11,10 +11,96 @@ type IPNet struct {
 	Net uint8
 }
 
+func NewIPNetFromCIDR(s string) (*IPNet, error) {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, _ := ipNet.Mask.Size()
+	return &IPNet{
+		IP:  ipNet.IP,
+		Net: uint8(ones),
+	}, nil
+}
+
 func (r *IPNet) String() string {
 	return fmt.Sprintf("%s/%d", r.IP.String(), r.Net)
 }
 
+func (r *IPNet) IsIPv4() bool {
+	return r.IP.To4() != nil
+}
+
+func (r *IPNet) ip() net.IP {
+	if ip := r.IP.To4(); ip != nil {
+		return ip
+	}
+
+	return r.IP.To16()
+}
+
+func (r *IPNet) masked(n uint8) net.IP {
+	ip := r.ip()
+
+	output := make(net.IP, len(ip))
+	for i := range ip {
+		bits := int(n) - i*8
+		switch {
+		case bits >= 8:
+			output[i] = ip[i]
+		case bits > 0:
+			output[i] = ip[i] & ^byte(0xff>>uint(bits))
+		}
+	}
+
+	return output
+}
+
+func (r *IPNet) Contains(v IPNet) bool {
+	if len(r.ip()) != len(v.ip()) || r.Net > v.Net {
+		return false
+	}
+
+	return r.masked(r.Net).Equal(v.masked(r.Net))
+}
+
+func (r *IPNet) Overlaps(v IPNet) bool {
+	return r.Contains(v) || v.Contains(*r)
+}
+
+// Split returns the two halves of the network.
+func (r *IPNet) Split() (IPNet, IPNet) {
+	var (
+		left  = r.masked(r.Net)
+		right = r.masked(r.Net)
+	)
+
+	right[r.Net/8] |= 0x80 >> (r.Net % 8)
+	return IPNet{IP: left, Net: r.Net + 1}, IPNet{IP: right, Net: r.Net + 1}
+}
+
+// Exclude returns the smallest ordered set of networks covering r except
+// the given networks.
+func (r *IPNet) Exclude(items []IPNet) []IPNet {
+	overlaps := false
+	for i := range items {
+		if items[i].Contains(*r) {
+			return nil
+		}
+		if r.Overlaps(items[i]) {
+			overlaps = true
+		}
+	}
+
+	if !overlaps {
+		return []IPNet{{IP: r.masked(r.Net), Net: r.Net}}
+	}
+
+	left, right := r.Split()
+	return append(left.Exclude(items), right.Exclude(items)...)
+}
+
 type Endpoint struct {
 	Host string
 	Port uint16
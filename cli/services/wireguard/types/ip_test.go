@@ -0,0 +1,84 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustIPNet(t *testing.T, s string) IPNet {
+	t.Helper()
+
+	v, err := NewIPNetFromCIDR(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *v
+}
+
+func ipNetsString(items []IPNet) string {
+	s := make([]string, 0, len(items))
+	for i := range items {
+		s = append(s, items[i].String())
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func TestIPNetExclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		excluded []string
+		expected string
+	}{
+		{"nothing", "0.0.0.0/0", nil, "0.0.0.0/0"},
+		{"no overlap", "10.0.0.0/8", []string{"192.168.0.0/16"}, "10.0.0.0/8"},
+		{"everything", "10.0.0.0/8", []string{"0.0.0.0/0"}, ""},
+		{"other family", "::/0", []string{"192.168.0.0/16"}, "::/0"},
+		{"half", "0.0.0.0/0", []string{"128.0.0.0/1"}, "0.0.0.0/1"},
+		{
+			"LAN", "0.0.0.0/0", []string{"192.168.0.0/16"},
+			"0.0.0.0/1, 128.0.0.0/2, 192.0.0.0/9, 192.128.0.0/11, 192.160.0.0/13, 192.169.0.0/16, " +
+				"192.170.0.0/15, 192.172.0.0/14, 192.176.0.0/12, 192.192.0.0/10, 193.0.0.0/8, 194.0.0.0/7, " +
+				"196.0.0.0/6, 200.0.0.0/5, 208.0.0.0/4, 224.0.0.0/3",
+		},
+		{
+			"several", "10.0.0.0/8", []string{"10.0.0.0/9", "10.192.0.0/10"},
+			"10.128.0.0/10",
+		},
+		{
+			"IPv6", "::/0", []string{"8000::/1", "4000::/2"},
+			"::/2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := mustIPNet(t, tt.from)
+
+			var excluded []IPNet
+			for _, item := range tt.excluded {
+				excluded = append(excluded, mustIPNet(t, item))
+			}
+
+			items := from.Exclude(excluded)
+			if v := ipNetsString(items); v != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, v)
+			}
+
+			for i := range items {
+				for j := range excluded {
+					if items[i].Overlaps(excluded[j]) {
+						t.Errorf("%s overlaps the excluded %s", items[i].String(), excluded[j].String())
+					}
+				}
+				for j := i + 1; j < len(items); j++ {
+					if items[i].Overlaps(items[j]) {
+						t.Errorf("%s overlaps %s", items[i].String(), items[j].String())
+					}
+				}
+			}
+		})
+	}
+}
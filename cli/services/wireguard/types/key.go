@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/base64"
+	"fmt"
 
 	"golang.org/x/crypto/curve25519"
 )
@@ -21,6 +22,18 @@ func NewKey(bytes []byte) *Key {
 	return &key
 }
 
+func NewKeyFromString(s string) (*Key, error) {
+	bytes, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes) != KeyLength {
+		return nil, fmt.Errorf("invalid key length %d; expected %d", len(bytes), KeyLength)
+	}
+
+	return NewKey(bytes), nil
+}
+
 func NewPresharedKey() (*Key, error) {
 	var key Key
 
@@ -52,6 +65,13 @@ func (k *Key) IsZero() bool {
 	return subtle.ConstantTimeCompare(zeros[:], k[:]) == 1
 }
 
+// Zero overwrites the key, so it does not stay in memory after use.
+func (k *Key) Zero() {
+	for i := range k {
+		k[i] = 0
+	}
+}
+
 func (k *Key) Public() *Key {
 	var p [KeyLength]byte
 	curve25519.ScalarBaseMult(&p, (*[KeyLength]byte)(k))
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	interfaceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_=+.-]+$`)
+)
+
+func InterfaceName(prefix string, id uint64) (string, error) {
+	name := fmt.Sprintf("%s%d", prefix, id)
+	if len(name) > MaxInterfaceNameLength {
+		return "", fmt.Errorf("interface name %s exceeds %d characters", name, MaxInterfaceNameLength)
+	}
+
+	return name, nil
+}
+
+// ValidateInterfaceName accepts the names wg-quick does for a config file.
+func ValidateInterfaceName(name string) error {
+	if !interfaceNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid interface name %q", name)
+	}
+	if len(name) > MaxInterfaceNameLength {
+		return fmt.Errorf("interface name %s exceeds %d characters", name, MaxInterfaceNameLength)
+	}
+
+	return nil
+}
+
+// ValidateInterfacePrefix accepts a prefix that leaves room for at least one
+// digit of the session ID.
+func ValidateInterfacePrefix(prefix string) error {
+	if !interfaceNameRegexp.MatchString(prefix) {
+		return fmt.Errorf("invalid interface prefix %q", prefix)
+	}
+	if len(prefix) >= MaxInterfaceNameLength {
+		return fmt.Errorf("interface prefix %s exceeds %d characters", prefix, MaxInterfaceNameLength-1)
+	}
+
+	return nil
+}
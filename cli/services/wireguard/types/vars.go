@@ -1,5 +1,17 @@
 package types
 
+import (
+	"net"
+)
+
 const (
-	DefaultInterface = "wg99"
+	DNSGuardTag            = "sentinel-dnsguard"
+	InterfacePrefix        = "wg"
+	KillSwitchTag          = "sentinel-killswitch"
+	MaxInterfaceNameLength = 15
+)
+
+var (
+	DefaultRouteIPv4 = IPNet{IP: net.IPv4zero.To4(), Net: 0}
+	DefaultRouteIPv6 = IPNet{IP: net.IPv6zero, Net: 0}
 )
@@ -2,31 +2,70 @@ package wireguard
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+	"github.com/sentinel-official/desktop-client/cli/utils"
 )
 
 type WireGuard struct {
-	cfg    *types.Config
-	cfgDir string
-	info   []byte
+	cfg        *types.Config
+	cfgDir     string
+	info       []byte
+	dnsGuard   bool
+	killSwitch bool
+	nodeAPI    types.Endpoint
+
+	mutex         sync.Mutex
+	reconfigMutex sync.Mutex
+	reconnectFn   ReconnectFunc
+	subscribers   map[chan types.Event]struct{}
+	monitorCancel func()
+	monitorDone   chan struct{}
 }
 
 func NewWireGuard() *WireGuard {
 	return &WireGuard{}
 }
 
-func (w *WireGuard) WithConfig(v *types.Config) *WireGuard { w.cfg = v; return w }
-func (w *WireGuard) WithConfigDir(v string) *WireGuard     { w.cfgDir = v; return w }
-func (w *WireGuard) WithInfo(v []byte) *WireGuard          { w.info = v; return w }
+func (w *WireGuard) WithConfig(v *types.Config) *WireGuard    { w.cfg = v; return w }
+func (w *WireGuard) WithConfigDir(v string) *WireGuard        { w.cfgDir = v; return w }
+func (w *WireGuard) WithInfo(v []byte) *WireGuard             { w.info = v; return w }
+func (w *WireGuard) WithDNSGuard(v bool) *WireGuard           { w.dnsGuard = v; return w }
+func (w *WireGuard) WithKillSwitch(v bool) *WireGuard         { w.killSwitch = v; return w }
+func (w *WireGuard) WithNodeAPI(v types.Endpoint) *WireGuard  { w.nodeAPI = v; return w }
+func (w *WireGuard) WithReconnect(v ReconnectFunc) *WireGuard { w.reconnectFn = v; return w }
 
 func (w *WireGuard) Info() []byte { return w.info }
 
+func (w *WireGuard) Config() *types.Config {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.cfg
+}
+
+func (w *WireGuard) DNS() []net.IP {
+	return w.Config().Interface.DNS
+}
+
+func (w *WireGuard) IsUp() bool {
+	iFace, err := w.RealInterface()
+	if err != nil {
+		return false
+	}
+
+	_, err = net.InterfaceByName(iFace)
+	return err == nil
+}
+
 func (w *WireGuard) Up() error {
 	cmd := exec.Command("wg-quick", strings.Split(
 		fmt.Sprintf("up %s", filepath.Join(w.cfgDir, fmt.Sprintf("%s.conf", w.cfg.Name))), " ")...)
@@ -36,8 +75,23 @@ func (w *WireGuard) Up() error {
 	return cmd.Run()
 }
 
-func (w *WireGuard) PostUp() error  { return nil }
-func (w *WireGuard) PreDown() error { return nil }
+func (w *WireGuard) PostUp() error {
+	if w.dnsGuard {
+		if err := w.enableDNSGuard(); err != nil {
+			return err
+		}
+	}
+	if w.killSwitch {
+		return w.enableKillSwitch()
+	}
+
+	return nil
+}
+
+func (w *WireGuard) PreDown() error {
+	w.stopMonitor()
+	return nil
+}
 
 func (w *WireGuard) Down() error {
 	cmd := exec.Command("wg-quick", strings.Split(
@@ -49,12 +103,29 @@ func (w *WireGuard) Down() error {
 }
 
 func (w *WireGuard) PostDown() error {
+	return utils.JoinErrors(w.postDown()...)
+}
+
+// postDown runs every step of PostDown, carrying on past the failed ones.
+func (w *WireGuard) postDown() []error {
+	var errs []error
+	if w.dnsGuard {
+		if err := w.disableDNSGuard(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore the DNS; %s", err))
+		}
+	}
+	if w.killSwitch {
+		if err := FlushKillSwitch(w.cfgDir); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush the kill switch; %s", err))
+		}
+	}
+
 	path := filepath.Join(w.cfgDir, fmt.Sprintf("%s.conf", w.cfg.Name))
-	if _, err := os.Stat(path); err == nil {
-		return os.Remove(path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, err)
 	}
 
-	return nil
+	return errs
 }
 
 func (w *WireGuard) Transfer() (int64, int64, error) {
@@ -91,3 +162,51 @@ func (w *WireGuard) Transfer() (int64, int64, error) {
 
 	return 0, 0, nil
 }
+
+func (w *WireGuard) LatestHandshake() (time.Time, error) {
+	iFace, err := w.RealInterface()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	output, err := exec.Command("wg", strings.Split(
+		fmt.Sprintf("show %s latest-handshakes", iFace), " ")...).Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	lines := strings.Split(string(output), "\n")
+	for _, line := range lines {
+		columns := strings.Split(line, "\t")
+		if len(columns) != 2 {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(columns[1], 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if seconds == 0 {
+			return time.Time{}, nil
+		}
+
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Time{}, nil
+}
+
+func (w *WireGuard) Dump() (*types.Dump, error) {
+	iFace, err := w.RealInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := exec.Command("wg", strings.Split(
+		fmt.Sprintf("show %s dump", iFace), " ")...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewDumpFromOutput(string(output))
+}
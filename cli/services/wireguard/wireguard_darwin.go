@@ -8,6 +8,12 @@ import (
 )
 
 func (w *WireGuard) PreUp() error {
+	if w.dnsGuard {
+		if err := w.snapshotDNS(); err != nil {
+			return err
+		}
+	}
+
 	return w.cfg.WriteToFile(w.cfgDir)
 }
 
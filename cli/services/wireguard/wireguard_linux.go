@@ -9,6 +9,12 @@ import (
 )
 
 func (w *WireGuard) PreUp() error {
+	if w.dnsGuard {
+		if err := w.snapshotDNS(); err != nil {
+			return err
+		}
+	}
+
 	iFace, err := nettest.RoutedInterface("ip", net.FlagUp|net.FlagBroadcast)
 	if err != nil {
 		return err
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+type BandwidthSample struct {
+	Time     time.Time `json:"time"`
+	Upload   int64     `json:"upload"`
+	Download int64     `json:"download"`
+}
+
+// BandwidthCounter turns a transfer counter that starts over whenever the
+// interface is recreated into a cumulative one. A reading lower than the
+// previous one is taken as a reset, so the previous reading becomes part of
+// the baseline rather than a negative delta.
+type BandwidthCounter struct {
+	base int64
+	last int64
+}
+
+func (c *BandwidthCounter) Update(v int64) int64 {
+	if v < c.last {
+		c.base += c.last
+	}
+
+	c.last = v
+	return c.base + v
+}
+
+// BandwidthSeries is a fixed size ring buffer of samples, once full the
+// oldest sample is overwritten.
+type BandwidthSeries struct {
+	mutex   sync.RWMutex
+	samples []BandwidthSample
+	next    int
+	full    bool
+}
+
+func NewBandwidthSeries(capacity int) *BandwidthSeries {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &BandwidthSeries{
+		samples: make([]BandwidthSample, capacity),
+	}
+}
+
+func (s *BandwidthSeries) Add(v BandwidthSample) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.samples[s.next] = v
+	s.next = (s.next + 1) % len(s.samples)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Samples returns a copy of the samples, oldest first.
+func (s *BandwidthSeries) Samples() []BandwidthSample {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.full {
+		return append([]BandwidthSample{}, s.samples[:s.next]...)
+	}
+
+	items := make([]BandwidthSample, 0, len(s.samples))
+	items = append(items, s.samples[s.next:]...)
+	return append(items, s.samples[:s.next]...)
+}
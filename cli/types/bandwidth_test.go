@@ -0,0 +1,29 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestBandwidthCounter(t *testing.T) {
+	tests := []struct {
+		name     string
+		readings []int64
+		expected []int64
+	}{
+		{"increasing", []int64{0, 10, 50}, []int64{0, 10, 50}},
+		{"steady", []int64{10, 10, 10}, []int64{10, 10, 10}},
+		{"resets", []int64{10, 50, 5, 20, 0, 7}, []int64{10, 50, 55, 70, 70, 77}},
+		{"decreasing", []int64{100, 80, 60, 40}, []int64{100, 180, 240, 280}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var counter BandwidthCounter
+			for i, v := range tt.readings {
+				if total := counter.Update(v); total != tt.expected[i] {
+					t.Fatalf("expected %d after reading %d at %d, got %d", tt.expected[i], v, i, total)
+				}
+			}
+		})
+	}
+}
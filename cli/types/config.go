@@ -5,11 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	neturl "net/url"
 	"os"
 	"strings"
 	"text/template"
 
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/pelletier/go-toml"
+
+	wgt "github.com/sentinel-official/desktop-client/cli/services/wireguard/types"
+)
+
+const (
+	redacted = "redacted"
 )
 
 var (
@@ -17,17 +25,66 @@ var (
 setup = {{ .Setup }}
 version = {{ .Version }}
 
+[auth]
+protect_reads = {{ .Auth.ProtectReads }}
+
+[bandwidth]
+interval = {{ .Bandwidth.Interval }}
+retention = {{ .Bandwidth.Retention }}
+
 [chain]
 broadcast_mode = "{{ .Chain.BroadcastMode }}"
 gas_adjustment = {{ .Chain.GasAdjustment }}
 gas = {{ .Chain.Gas }}
 gas_prices = "{{ .Chain.GasPrices }}"
 id = "{{ .Chain.ID }}"
+query_attempts = {{ .Chain.QueryAttempts }}
+query_backoff = {{ .Chain.QueryBackoff }}
 rpc_address = "{{ .Chain.RPCAddress }}"
+rpc_fallback_addresses = [{{ range $i, $v := .Chain.RPCFallbackAddresses }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
 simulate_and_execute = {{ .Chain.SimulateAndExecute }}
 
 [cors]
-allowed_origins = "{{ .CORS.AllowedOrigins }}"
+allowed_origins = [{{ range $i, $v := .CORS.AllowedOrigins }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+
+[keyring]
+backend = "{{ .Keyring.Backend }}"
+unlock_ttl = {{ .Keyring.UnlockTTL }}
+
+[log]
+level = "{{ .Log.Level }}"
+node_responses = {{ .Log.NodeResponses }}
+
+[node]
+proxy_url = "{{ .Node.ProxyURL }}"
+timeout = {{ .Node.Timeout }}
+
+[rate_limit]
+session_burst = {{ .RateLimit.SessionBurst }}
+sessions_per_minute = {{ .RateLimit.SessionsPerMinute }}
+
+[reconnect]
+attempts = {{ .Reconnect.Attempts }}
+enabled = {{ .Reconnect.Enabled }}
+interval = {{ .Reconnect.Interval }}
+stale_threshold = {{ .Reconnect.StaleThreshold }}
+
+[server]
+listen_url = "{{ .Server.ListenURL }}"
+max_body_size = {{ .Server.MaxBodySize }}
+tls_crt = "{{ .Server.TLSCrt }}"
+tls_enabled = {{ .Server.TLSEnabled }}
+tls_key = "{{ .Server.TLSKey }}"
+
+[session]
+expiry_interval = {{ .Session.ExpiryInterval }}
+
+[webhook]
+timeout = {{ .Webhook.Timeout }}
+url = "{{ .Webhook.URL }}"
+
+[wireguard]
+interface_prefix = "{{ .WireGuard.InterfacePrefix }}"
 	`)
 
 	t = func() *template.Template {
@@ -43,18 +100,67 @@ allowed_origins = "{{ .CORS.AllowedOrigins }}"
 type Config struct {
 	Setup   bool   `json:"setup"`
 	Version uint64 `json:"version"`
-	Chain   struct {
-		BroadcastMode      string  `json:"broadcast_mode"`
-		GasAdjustment      float64 `json:"gas_adjustment"`
-		GasPrices          string  `json:"gas_prices"`
-		Gas                uint64  `json:"gas"`
-		ID                 string  `json:"id"`
-		RPCAddress         string  `json:"rpc_address"`
-		SimulateAndExecute bool    `json:"simulate_and_execute"`
+	Auth    struct {
+		ProtectReads bool `json:"protect_reads"`
+	} `json:"auth"`
+	Bandwidth struct {
+		Interval  uint64 `json:"interval"`
+		Retention uint64 `json:"retention"`
+	} `json:"bandwidth"`
+	Chain struct {
+		BroadcastMode        string   `json:"broadcast_mode"`
+		GasAdjustment        float64  `json:"gas_adjustment"`
+		GasPrices            string   `json:"gas_prices"`
+		Gas                  uint64   `json:"gas"`
+		ID                   string   `json:"id"`
+		QueryAttempts        uint64   `json:"query_attempts"`
+		QueryBackoff         uint64   `json:"query_backoff"`
+		RPCAddress           string   `json:"rpc_address"`
+		RPCFallbackAddresses []string `json:"rpc_fallback_addresses"`
+		SimulateAndExecute   bool     `json:"simulate_and_execute"`
 	} `json:"chain"`
 	CORS struct {
-		AllowedOrigins string `json:"allowed_origins"`
+		AllowedOrigins []string `json:"allowed_origins"`
 	} `json:"cors"`
+	Keyring struct {
+		Backend   string `json:"backend"`
+		UnlockTTL uint64 `json:"unlock_ttl"`
+	} `json:"keyring"`
+	Log struct {
+		Level         string `json:"level"`
+		NodeResponses bool   `json:"node_responses"`
+	} `json:"log"`
+	Node struct {
+		ProxyURL string `json:"proxy_url"`
+		Timeout  uint64 `json:"timeout"`
+	} `json:"node"`
+	RateLimit struct {
+		SessionBurst      uint64  `json:"session_burst"`
+		SessionsPerMinute float64 `json:"sessions_per_minute"`
+	} `json:"rate_limit"`
+	Reconnect struct {
+		Attempts       uint64 `json:"attempts"`
+		Enabled        bool   `json:"enabled"`
+		Interval       uint64 `json:"interval"`
+		StaleThreshold uint64 `json:"stale_threshold"`
+	} `json:"reconnect"`
+	Server struct {
+		ListenURL   string `json:"listen_url"`
+		MaxBodySize uint64 `json:"max_body_size"`
+		TLSCrt      string `json:"tls_crt"`
+		TLSEnabled  bool   `json:"tls_enabled"`
+		TLSKey      string `json:"tls_key"`
+	} `json:"server"`
+	Session struct {
+		ExpiryInterval uint64 `json:"expiry_interval"`
+	} `json:"session"`
+	Webhook struct {
+		Timeout uint64 `json:"timeout"`
+		URL     string `json:"url"`
+	} `json:"webhook"`
+	WireGuard struct {
+		InterfacePrefix string `json:"interface_prefix"`
+	} `json:"wireguard"`
 }
 
 func NewConfig() *Config {
@@ -62,25 +168,72 @@ func NewConfig() *Config {
 }
 
 func (c *Config) Copy() *Config {
-	return &Config{
-		Setup:   c.Setup,
-		Version: c.Version,
-		Chain:   c.Chain,
-		CORS:    c.CORS,
+	v := &Config{
+		Setup:     c.Setup,
+		Version:   c.Version,
+		Auth:      c.Auth,
+		Bandwidth: c.Bandwidth,
+		Chain:     c.Chain,
+		CORS:      c.CORS,
+		Keyring:   c.Keyring,
+		Log:       c.Log,
+		Node:      c.Node,
+		RateLimit: c.RateLimit,
+		Reconnect: c.Reconnect,
+		Server:    c.Server,
+		Session:   c.Session,
+		Webhook:   c.Webhook,
+		WireGuard: c.WireGuard,
 	}
+
+	v.Chain.RPCFallbackAddresses = append([]string(nil), c.Chain.RPCFallbackAddresses...)
+	v.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+	return v
 }
 
 func (c *Config) WithDefaultValues() *Config {
 	c.Setup = true
-	c.Version = 2
+	c.Version = 21
+	c.Auth.ProtectReads = true
+	c.Bandwidth.Interval = 5
+	c.Bandwidth.Retention = 3600
 	c.Chain.BroadcastMode = "block"
 	c.Chain.Gas = 5e5
 	c.Chain.GasAdjustment = 1.05
 	c.Chain.GasPrices = "0.1udvpn"
 	c.Chain.ID = "sentinelhub-2"
+	c.Chain.QueryAttempts = 3
+	c.Chain.QueryBackoff = 1
 	c.Chain.RPCAddress = "https://rpc.sentinel.co:443"
+	c.Chain.RPCFallbackAddresses = []string{}
 	c.Chain.SimulateAndExecute = false
-	c.CORS.AllowedOrigins = ""
+	c.CORS.AllowedOrigins = []string{
+		"http://127.0.0.1",
+		"http://127.0.0.1:*",
+		"http://localhost",
+		"http://localhost:*",
+	}
+	c.Keyring.Backend = keyring.BackendOS
+	c.Keyring.UnlockTTL = 300
+	c.Log.Level = LogLevelInfo
+	c.Log.NodeResponses = false
+	c.Node.ProxyURL = ""
+	c.Node.Timeout = 15
+	c.RateLimit.SessionBurst = 3
+	c.RateLimit.SessionsPerMinute = 6
+	c.Reconnect.Attempts = 3
+	c.Reconnect.Enabled = true
+	c.Reconnect.Interval = 30
+	c.Reconnect.StaleThreshold = 180
+	c.Server.ListenURL = DefaultListenURL
+	c.Server.MaxBodySize = 1 << 20
+	c.Server.TLSCrt = ""
+	c.Server.TLSEnabled = false
+	c.Server.TLSKey = ""
+	c.Session.ExpiryInterval = 60
+	c.Webhook.Timeout = 5
+	c.Webhook.URL = ""
+	c.WireGuard.InterfacePrefix = wgt.InterfacePrefix
 
 	return c
 }
@@ -131,7 +284,49 @@ func (c *Config) String() string {
 	return buffer.String()
 }
 
+// redactURL hides the password and anything after the host of a URL, where
+// secrets such as tokens are usually kept.
+func redactURL(v string) string {
+	if v == "" {
+		return ""
+	}
+
+	u, err := neturl.Parse(v)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = neturl.UserPassword(u.User.Username(), redacted)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		u.Path, u.RawPath, u.RawQuery, u.Fragment = "/"+redacted, "", "", ""
+	}
+
+	return u.String()
+}
+
+// Redacted returns a copy of the config that is safe to show, with the
+// secrets the node proxy and webhook URLs may carry hidden.
+func (c *Config) Redacted() *Config {
+	v := c.Copy()
+	v.Node.ProxyURL = redactURL(v.Node.ProxyURL)
+	v.Webhook.URL = redactURL(v.Webhook.URL)
+
+	return v
+}
+
+// RPCAddresses returns the chain RPC address followed by its fallbacks.
+func (c *Config) RPCAddresses() []string {
+	return append([]string{c.Chain.RPCAddress}, c.Chain.RPCFallbackAddresses...)
+}
+
 func (c *Config) Validate() error {
+	if c.Bandwidth.Interval == 0 {
+		return fmt.Errorf("invalid bandwidth->interval; expected positive value")
+	}
+	if c.Bandwidth.Retention < c.Bandwidth.Interval {
+		return fmt.Errorf("invalid bandwidth->retention; expected value not less than bandwidth->interval")
+	}
 	if c.Chain.BroadcastMode == "" {
 		return fmt.Errorf("invalid chain->broadcast_mode; expected non-empty value")
 	}
@@ -141,9 +336,80 @@ func (c *Config) Validate() error {
 	if c.Chain.ID == "" {
 		return fmt.Errorf("invalid chain->id; expected non-empty value")
 	}
+	if c.Chain.QueryAttempts == 0 {
+		return fmt.Errorf("invalid chain->query_attempts; expected positive value")
+	}
 	if c.Chain.RPCAddress == "" {
 		return fmt.Errorf("invalid chain->rpc_address; expected non-empty value")
 	}
+	for _, address := range c.Chain.RPCFallbackAddresses {
+		if address == "" {
+			return fmt.Errorf("invalid chain->rpc_fallback_addresses; expected non-empty values")
+		}
+	}
+	if !IsValidKeyringBackend(c.Keyring.Backend) {
+		return fmt.Errorf("invalid keyring->backend; expected one of os, file, test")
+	}
+	if c.Keyring.UnlockTTL == 0 {
+		return fmt.Errorf("invalid keyring->unlock_ttl; expected positive value")
+	}
+	if !IsValidLogLevel(c.Log.Level) {
+		return fmt.Errorf("invalid log->level; expected one of debug, info, warn, error")
+	}
+	if c.Node.ProxyURL != "" {
+		u, err := neturl.Parse(c.Node.ProxyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			return fmt.Errorf("invalid node->proxy_url; expected http, https or socks5 URL")
+		}
+	}
+	if c.RateLimit.SessionBurst == 0 {
+		return fmt.Errorf("invalid rate_limit->session_burst; expected positive value")
+	}
+	if c.RateLimit.SessionsPerMinute <= 0 {
+		return fmt.Errorf("invalid rate_limit->sessions_per_minute; expected positive value")
+	}
+	if c.Reconnect.Enabled {
+		if c.Reconnect.Attempts == 0 {
+			return fmt.Errorf("invalid reconnect->attempts; expected positive value")
+		}
+		if c.Reconnect.Interval == 0 {
+			return fmt.Errorf("invalid reconnect->interval; expected positive value")
+		}
+		if c.Reconnect.StaleThreshold == 0 {
+			return fmt.Errorf("invalid reconnect->stale_threshold; expected positive value")
+		}
+	}
+	url, err := ParseListenURL(c.Server.ListenURL)
+	if err != nil {
+		return fmt.Errorf("invalid server->listen_url; %s", err)
+	}
+	if c.Server.TLSEnabled && url.Scheme != "https" {
+		return fmt.Errorf("invalid server->listen_url; expected https scheme with server->tls_enabled")
+	}
+	if !c.Server.TLSEnabled && url.Scheme == "https" {
+		return fmt.Errorf("invalid server->listen_url; expected server->tls_enabled with https scheme")
+	}
+	if c.Server.MaxBodySize == 0 {
+		return fmt.Errorf("invalid server->max_body_size; expected positive value")
+	}
+	if (c.Server.TLSCrt == "") != (c.Server.TLSKey == "") {
+		return fmt.Errorf("invalid server->tls_crt and server->tls_key; expected both or neither")
+	}
+	if c.Session.ExpiryInterval == 0 {
+		return fmt.Errorf("invalid session->expiry_interval; expected positive value")
+	}
+	if c.Webhook.URL != "" {
+		u, err := neturl.Parse(c.Webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook->url; expected http or https URL")
+		}
+	}
+	if c.Webhook.Timeout == 0 {
+		return fmt.Errorf("invalid webhook->timeout; expected positive value")
+	}
+	if err := wgt.ValidateInterfacePrefix(c.WireGuard.InterfacePrefix); err != nil {
+		return fmt.Errorf("invalid wireguard->interface_prefix; %s", err)
+	}
 
 	return nil
 }
@@ -2,8 +2,10 @@ package types
 
 type Error struct {
 	Code    int    `json:"code"`
+	ID      string `json:"id,omitempty"`
 	Message string `json:"message"`
 	Module  string `json:"module,omitempty"`
+	Cause   *Error `json:"cause,omitempty"`
 }
 
 func NewError(module string, code int, message string) *Error {
@@ -13,3 +15,37 @@ func NewError(module string, code int, message string) *Error {
 		Module:  module,
 	}
 }
+
+func (e *Error) WithCause(v *Error) *Error { e.Cause = v; return e }
+func (e *Error) WithID(v string) *Error    { e.ID = v; return e }
+
+// ErrorCode pairs a numeric error code with a stable identifier, so clients
+// can branch on failures without parsing the message. The numeric values are
+// kept as they were before identifiers existed.
+type ErrorCode struct {
+	Code int
+	ID   string
+}
+
+func NewErrorCode(code int, id string) ErrorCode {
+	return ErrorCode{
+		Code: code,
+		ID:   id,
+	}
+}
+
+type TxError struct {
+	Codespace string
+	Code      uint32
+	Log       string
+}
+
+func NewTxError(codespace string, code uint32, log string) *TxError {
+	return &TxError{
+		Codespace: codespace,
+		Code:      code,
+		Log:       log,
+	}
+}
+
+func (e *TxError) Error() string { return e.Log }
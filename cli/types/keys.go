@@ -3,10 +3,11 @@ package types
 import (
 	"os"
 	"path/filepath"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 )
 
 var (
-	Version              = ""
 	DefaultListenURL     = "http://127.0.0.1:26667"
 	DefaultHomeDirectory = func() string {
 		home, err := os.UserHomeDir()
@@ -18,3 +19,17 @@ var (
 	}()
 	Denom = "udvpn"
 )
+
+const (
+	ContentTypeJSON = "application/json; charset=utf-8"
+	TokenFileName   = "token"
+)
+
+func IsValidKeyringBackend(v string) bool {
+	switch v {
+	case keyring.BackendOS, keyring.BackendFile, keyring.BackendTest:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,26 @@
+package types
+
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+var (
+	logLevels = map[string]int{
+		LogLevelDebug: 0,
+		LogLevelInfo:  1,
+		LogLevelWarn:  2,
+		LogLevelError: 3,
+	}
+)
+
+func IsValidLogLevel(level string) bool {
+	_, ok := logLevels[level]
+	return ok
+}
+
+func IsLogLevelEnabled(level, min string) bool {
+	return logLevels[level] >= logLevels[min]
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"bufio"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
 )
@@ -13,8 +16,9 @@ type Response struct {
 
 type ResponseWriter struct {
 	http.ResponseWriter
-	Status int
-	Length int
+	Status    int
+	Length    int
+	ErrorCode int
 }
 
 func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
@@ -30,17 +34,36 @@ func (r *ResponseWriter) Header() http.Header {
 }
 
 func (r *ResponseWriter) Write(p []byte) (n int, err error) {
+	if r.Status == 0 {
+		r.Status = http.StatusOK
+	}
+
 	n, err = r.ResponseWriter.Write(p)
 	r.Length += n
 
 	return n, err
 }
 
+// WriteHeader ignores calls after the first one, which net/http would only
+// warn about as superfluous.
 func (r *ResponseWriter) WriteHeader(status int) {
+	if r.Status != 0 {
+		return
+	}
+
 	r.ResponseWriter.WriteHeader(status)
 	r.Status = status
 }
 
+func (r *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not implement http.Hijacker")
+	}
+
+	return hijacker.Hijack()
+}
+
 type Token struct {
 	Value  string    `json:"value"`
 	Expiry time.Time `json:"expiry"`
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseWriterStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  func(w http.ResponseWriter)
+		status int
+	}{
+		{"implicit", func(w http.ResponseWriter) { _, _ = w.Write([]byte("{}")) }, http.StatusOK},
+		{"explicit", func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) }, http.StatusNotFound},
+		{"repeated", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.WriteHeader(http.StatusInternalServerError)
+		}, http.StatusBadRequest},
+		{"after a write", func(w http.ResponseWriter) {
+			_, _ = w.Write([]byte("{}"))
+			w.WriteHeader(http.StatusInternalServerError)
+		}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			w := NewResponseWriter(recorder)
+
+			tt.write(w)
+			if w.Status != tt.status {
+				t.Fatalf("expected recorded status %d, got %d", tt.status, w.Status)
+			}
+			if recorder.Code != tt.status {
+				t.Fatalf("expected written status %d, got %d", tt.status, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	neturl "net/url"
+	"runtime"
+	"strconv"
+)
+
+// ParseListenURL parses an http://host:port, https://host:port or
+// unix:///path/to/socket listen URL.
+func ParseListenURL(v string) (*neturl.URL, error) {
+	url, err := neturl.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch url.Scheme {
+	case "http", "https":
+		host, port, err := net.SplitHostPort(url.Host)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) == nil && host != "localhost" {
+			return nil, fmt.Errorf("invalid listen host %s; expected IP address or localhost", host)
+		}
+		if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid listen port %s", port)
+		}
+	case "unix":
+		if runtime.GOOS == "windows" {
+			return nil, fmt.Errorf("unix sockets are not supported on windows")
+		}
+		if url.Host != "" || url.Path == "" {
+			return nil, fmt.Errorf("invalid unix socket URL; expected unix:///path/to/socket")
+		}
+	default:
+		return nil, fmt.Errorf("invalid listen URL scheme %s; expected one of http, https, unix", url.Scheme)
+	}
+
+	return url, nil
+}
@@ -2,8 +2,27 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+)
+
+const (
+	ServiceTypeWireGuard = "wireguard"
+	ServiceTypeV2Ray     = "v2ray"
+)
+
+const (
+	NetworkDual = "dual"
+	NetworkIPv4 = "ipv4"
+	NetworkIPv6 = "ipv6"
+)
+
+const (
+	RoutingFull      = "full"
+	RoutingNone      = "none"
+	RoutingLANExempt = "lan-exempt"
 )
 
 type Service interface {
@@ -18,20 +37,32 @@ type Service interface {
 }
 
 type Status struct {
-	From string `json:"from"`
-	ID   uint64 `json:"id"`
-	Name string `json:"name"`
-	To   string `json:"to"`
+	DNSGuard   bool   `json:"dns_guard"`
+	From       string `json:"from"`
+	ID         uint64 `json:"id"`
+	KillSwitch bool   `json:"kill_switch"`
+	Name       string `json:"name"`
+	RemoteURL  string `json:"remote_url"`
+	To         string `json:"to"`
+	Type       string `json:"type"`
+}
+
+func StatusFilePath(home string, id uint64) string {
+	return filepath.Join(home, fmt.Sprintf("status-%d.json", id))
 }
 
 func NewStatus() *Status {
 	return &Status{}
 }
 
-func (s *Status) WithFrom(v string) *Status { s.From = v; return s }
-func (s *Status) WithID(v uint64) *Status   { s.ID = v; return s }
-func (s *Status) WithName(v string) *Status { s.Name = v; return s }
-func (s *Status) WithTo(v string) *Status   { s.To = v; return s }
+func (s *Status) WithDNSGuard(v bool) *Status    { s.DNSGuard = v; return s }
+func (s *Status) WithFrom(v string) *Status      { s.From = v; return s }
+func (s *Status) WithID(v uint64) *Status        { s.ID = v; return s }
+func (s *Status) WithKillSwitch(v bool) *Status  { s.KillSwitch = v; return s }
+func (s *Status) WithName(v string) *Status      { s.Name = v; return s }
+func (s *Status) WithRemoteURL(v string) *Status { s.RemoteURL = v; return s }
+func (s *Status) WithTo(v string) *Status        { s.To = v; return s }
+func (s *Status) WithType(v string) *Status      { s.Type = v; return s }
 
 func (s *Status) LoadFromPath(path string) error {
 	if _, err := os.Stat(path); err != nil {
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TxOptions overrides the chain defaults of the config for a single
+// transaction. Zero values fall back to chain.gas, chain.gas_adjustment
+// and chain.gas_prices, and gas is simulated when Simulate is set or
+// chain.simulate_and_execute is enabled.
+type TxOptions struct {
+	Memo          string  `json:"memo"`
+	Gas           uint64  `json:"gas"`
+	GasAdjustment float64 `json:"gas_adjustment"`
+	GasPrices     string  `json:"gas_prices"`
+	Fees          string  `json:"fees"`
+	Simulate      bool    `json:"simulate"`
+}
+
+func (o *TxOptions) Validate() error {
+	if o.GasAdjustment < 0 {
+		return fmt.Errorf("invalid field GasAdjustment")
+	}
+	if o.GasPrices != "" {
+		if _, err := sdk.ParseDecCoins(o.GasPrices); err != nil {
+			return fmt.Errorf("invalid field GasPrices")
+		}
+	}
+	if o.Fees != "" {
+		if _, err := sdk.ParseCoinsNormalized(o.Fees); err != nil {
+			return fmt.Errorf("invalid field Fees")
+		}
+	}
+	if o.Fees != "" && o.GasPrices != "" {
+		return fmt.Errorf("invalid fields Fees and GasPrices; expected only one")
+	}
+
+	return nil
+}
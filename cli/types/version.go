@@ -0,0 +1,67 @@
+package types
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time with -ldflags "-X ...", see the Makefile.
+var (
+	Version   = ""
+	Commit    = ""
+	BuildDate = ""
+)
+
+type BuildInfo struct {
+	Version          string `json:"version"`
+	Commit           string `json:"commit"`
+	BuildDate        string `json:"build_date"`
+	GoVersion        string `json:"go_version"`
+	CosmosSDKVersion string `json:"cosmos_sdk_version"`
+	HubVersion       string `json:"hub_version"`
+}
+
+// NewBuildInfo returns the build details, falling back to the module
+// information embedded in the binary for whatever wasn't set at build time.
+func NewBuildInfo() BuildInfo {
+	v := BuildInfo{
+		Version:          Version,
+		Commit:           Commit,
+		BuildDate:        BuildDate,
+		GoVersion:        runtime.Version(),
+		CosmosSDKVersion: "unknown",
+		HubVersion:       "unknown",
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v.Version == "" && info.Main.Version != "(devel)" {
+			v.Version = info.Main.Version
+		}
+
+		for _, dep := range info.Deps {
+			version := dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Version
+			}
+
+			switch dep.Path {
+			case "github.com/cosmos/cosmos-sdk":
+				v.CosmosSDKVersion = version
+			case "github.com/sentinel-official/hub":
+				v.HubVersion = version
+			}
+		}
+	}
+
+	if v.Version == "" {
+		v.Version = "dev"
+	}
+	if v.Commit == "" {
+		v.Commit = "unknown"
+	}
+	if v.BuildDate == "" {
+		v.BuildDate = "unknown"
+	}
+
+	return v
+}
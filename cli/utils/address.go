@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ParseAccAddress accepts an account address in bech32 with the chain's
+// prefix, or as hex, as shown by some explorers.
+func ParseAccAddress(s string) (sdk.AccAddress, error) {
+	prefix := sdk.GetConfig().GetBech32AccountAddrPrefix()
+	if strings.HasPrefix(strings.ToLower(s), prefix+"1") {
+		address, err := sdk.AccAddressFromBech32(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bech32 address %s; %s", s, err)
+		}
+
+		return address, nil
+	}
+
+	bytes, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(s), "0x"))
+	if err != nil || len(bytes) == 0 {
+		return nil, fmt.Errorf("invalid address %s; expected bech32 with prefix %s or hex", s, prefix)
+	}
+	if err := sdk.VerifyAddressFormat(bytes); err != nil {
+		return nil, fmt.Errorf("invalid address %s; %s", s, err)
+	}
+
+	return bytes, nil
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	hubtypes "github.com/sentinel-official/hub/types"
+)
+
+func TestParseAccAddress(t *testing.T) {
+	// The hub sets the bech32 prefixes of the chain when it is loaded.
+	_ = hubtypes.GetConfig()
+
+	var (
+		address = sdk.AccAddress(bytes.Repeat([]byte{0x5e}, 20))
+		other   = sdk.AccAddress(bytes.Repeat([]byte{0x7a}, 20))
+	)
+
+	otherPrefix, err := bech32.ConvertAndEncode("cosmos", other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		s        string
+		expected sdk.AccAddress
+		err      bool
+	}{
+		{"bech32", address.String(), address, false},
+		{"hex", hex.EncodeToString(address), address, false},
+		{"upper case hex", strings.ToUpper(hex.EncodeToString(address)), address, false},
+		{"hex with 0x", "0x" + hex.EncodeToString(address), address, false},
+		{"bech32 with a bad checksum", address.String()[:len(address.String())-1] + "q", nil, true},
+		{"bech32 of another chain", otherPrefix, nil, true},
+		{"garbage", "not an address", nil, true},
+		{"odd length hex", hex.EncodeToString(address)[1:], nil, true},
+		{"empty", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseAccAddress(tt.s)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if !v.Equals(tt.expected) {
+				t.Fatalf("expected address %s, got %s", tt.expected, v)
+			}
+		})
+	}
+}
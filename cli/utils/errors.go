@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"strings"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -12,3 +14,31 @@ func IsNotFoundError(v error) error {
 
 	return v
 }
+
+// Errors is the error of a sequence of steps that each ran regardless of the
+// ones before failing.
+type Errors []error
+
+func (e Errors) Error() string {
+	items := make([]string, 0, len(e))
+	for _, err := range e {
+		items = append(items, err.Error())
+	}
+
+	return strings.Join(items, "; ")
+}
+
+// JoinErrors returns the non-nil errors as Errors, or nil when there are none.
+func JoinErrors(errs ...error) error {
+	var items Errors
+	for _, err := range errs {
+		if err != nil {
+			items = append(items, err)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	return items
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"net"
 )
 
@@ -21,3 +22,30 @@ func GetFreeUDPPort() (uint16, error) {
 
 	return uint16(conn.LocalAddr().(*net.UDPAddr).Port), nil
 }
+
+func CheckUDPPort(port uint16) error {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: int(port)})
+	if err != nil {
+		return fmt.Errorf("UDP port %d is already in use", port)
+	}
+
+	return conn.Close()
+}
+
+func GetFreeTCPPort() (uint16, error) {
+	addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+
+	listener, err := net.ListenTCP("tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	return uint16(listener.Addr().(*net.TCPAddr).Port), nil
+}
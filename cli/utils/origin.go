@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+)
+
+// IsOriginAllowed matches the origin against the allowed origins the way the
+// CORS handler does: case-insensitively, with "*" allowing every origin, an
+// empty list the same, and at most one "*" in an origin matching any part of
+// it.
+func IsOriginAllowed(origins []string, origin string) bool {
+	if len(origins) == 0 {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	for _, item := range origins {
+		item = strings.ToLower(item)
+		if item == "*" || item == origin {
+			return true
+		}
+
+		i := strings.IndexByte(item, '*')
+		if i < 0 {
+			continue
+		}
+
+		prefix, suffix := item[:i], item[i+1:]
+		if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
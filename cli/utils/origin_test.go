@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestIsOriginAllowed(t *testing.T) {
+	origins := []string{"http://127.0.0.1", "http://127.0.0.1:*", "https://*.example.com"}
+
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		allowed bool
+	}{
+		{"exact", origins, "http://127.0.0.1", true},
+		{"upper case", origins, "HTTP://127.0.0.1", true},
+		{"any port", origins, "http://127.0.0.1:3000", true},
+		{"subdomain", origins, "https://app.example.com", true},
+		{"other host", origins, "http://evil.test", false},
+		{"other scheme", origins, "https://127.0.0.1", false},
+		{"suffix only", origins, "https://example.com", false},
+		{"prefix of an allowed host", origins, "http://127.0.0.1.evil.test", false},
+		{"everything", []string{"*"}, "http://evil.test", true},
+		{"none configured", nil, "http://evil.test", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed := IsOriginAllowed(tt.origins, tt.origin); allowed != tt.allowed {
+				t.Fatalf("expected %v, got %v", tt.allowed, allowed)
+			}
+		})
+	}
+}
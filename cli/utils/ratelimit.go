@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter is a token bucket per key, refilled at rate tokens per second
+// up to burst tokens.
+type RateLimiter struct {
+	mutex   sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+}
+
+func NewRateLimiter(rate float64, burst uint64) *RateLimiter {
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token for the key. When none is left it returns false and
+// how long until one is.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	for k, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, k)
+		}
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := math.Ceil((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, time.Duration(wait)
+}
+
+func (l *RateLimiter) refill(b *bucket, now time.Time) float64 {
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	return b.tokens
+}
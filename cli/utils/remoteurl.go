@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	fingerprintFragmentPrefix = "sha256="
+)
+
+// SplitNodeRemoteURL splits the fingerprint of the node TLS certificate off
+// the remote URL of a node record. The hub keeps no certificate for a node,
+// so a node publishes the SHA-256 of its certificate on chain as a fragment
+// of the form sha256=<hex>, which is never sent to the node. The fingerprint
+// is nil when the URL has none.
+func SplitNodeRemoteURL(v string) (string, []byte, error) {
+	i := strings.IndexByte(v, '#')
+	if i < 0 || !strings.HasPrefix(v[i+1:], fingerprintFragmentPrefix) {
+		return v, nil, nil
+	}
+
+	fingerprint, err := hex.DecodeString(strings.TrimPrefix(v[i+1:], fingerprintFragmentPrefix))
+	if err != nil || len(fingerprint) != sha256.Size {
+		return "", nil, fmt.Errorf("invalid certificate fingerprint in node remote URL %q", v)
+	}
+
+	return v[:i], fingerprint, nil
+}
+
+// NodeTLSConfig returns the TLS config for a connection to a node. The
+// certificate is pinned to the fingerprint from the node record when there is
+// one, and verified against the system roots otherwise.
+func NodeTLSConfig(fingerprint []byte) *tls.Config {
+	if fingerprint == nil {
+		return &tls.Config{}
+	}
+
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("node did not present a certificate")
+			}
+
+			sum := sha256.Sum256(rawCerts[0])
+			if !bytes.Equal(sum[:], fingerprint) {
+				return fmt.Errorf("node certificate fingerprint mismatch")
+			}
+
+			return nil
+		},
+	}
+}
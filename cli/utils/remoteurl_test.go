@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSplitNodeRemoteURL(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name        string
+		input       string
+		remoteURL   string
+		fingerprint []byte
+		err         bool
+	}{
+		{"no fragment", "https://1.2.3.4:8585", "https://1.2.3.4:8585", nil, false},
+		{"other fragment", "https://1.2.3.4:8585#x", "https://1.2.3.4:8585#x", nil, false},
+		{"fingerprint", "https://1.2.3.4:8585#sha256=" + hash, "https://1.2.3.4:8585", bytes.Repeat([]byte{0xab}, 32), false},
+		{"short fingerprint", "https://1.2.3.4:8585#sha256=abab", "", nil, true},
+		{"bad hex", "https://1.2.3.4:8585#sha256=" + strings.Repeat("zz", 32), "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteURL, fingerprint, err := SplitNodeRemoteURL(tt.input)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if remoteURL != tt.remoteURL {
+				t.Errorf("expected remote URL %q, got %q", tt.remoteURL, remoteURL)
+			}
+			if !bytes.Equal(fingerprint, tt.fingerprint) {
+				t.Errorf("expected fingerprint %x, got %x", tt.fingerprint, fingerprint)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	resolverDialTimeout = 5 * time.Second
+)
+
+// NewResolver returns a resolver which queries the DNS servers in turn, or the
+// system resolver when there are none.
+func NewResolver(servers []net.IP) *net.Resolver {
+	if len(servers) == 0 {
+		return net.DefaultResolver
+	}
+
+	var next uint32
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			server := servers[int(atomic.AddUint32(&next, 1)-1)%len(servers)]
+
+			dialer := net.Dialer{Timeout: resolverDialTimeout}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(server.String(), "53"))
+		},
+	}
+}
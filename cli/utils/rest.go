@@ -3,27 +3,89 @@ package utils
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/query"
+	hubtypes "github.com/sentinel-official/hub/types"
 
 	"github.com/sentinel-official/desktop-client/cli/types"
 )
 
+const (
+	defaultPaginationLimit = 25
+	maxPaginationLimit     = 100
+)
+
 func write(w http.ResponseWriter, status int, res types.Response) error {
+	w.Header().Set("Content-Type", types.ContentTypeJSON)
 	w.WriteHeader(status)
 	return json.NewEncoder(w).Encode(res)
 }
 
+func setErrorCode(w http.ResponseWriter, code int) {
+	if rw, ok := w.(*types.ResponseWriter); ok {
+		rw.ErrorCode = code
+	}
+}
+
 func WriteErrorToResponse(w http.ResponseWriter, status, code int, message string) {
+	setErrorCode(w, code)
 	_ = write(w, status, types.Response{
 		Success: false,
 		Error:   types.NewError("", code, message),
 	})
 }
 
+func WriteErrorWithCauseToResponse(w http.ResponseWriter, status, code int, message string, cause *types.Error) {
+	setErrorCode(w, code)
+	_ = write(w, status, types.Response{
+		Success: false,
+		Error:   types.NewError("", code, message).WithCause(cause),
+	})
+}
+
+func WriteCodedErrorToResponse(w http.ResponseWriter, status int, code types.ErrorCode, message string) {
+	setErrorCode(w, code.Code)
+	_ = write(w, status, types.Response{
+		Success: false,
+		Error:   types.NewError("", code.Code, message).WithID(code.ID),
+	})
+}
+
+func WriteCodedErrorWithCauseToResponse(w http.ResponseWriter, status int, code types.ErrorCode, message string, cause *types.Error) {
+	setErrorCode(w, code.Code)
+	_ = write(w, status, types.Response{
+		Success: false,
+		Error:   types.NewError("", code.Code, message).WithID(code.ID).WithCause(cause),
+	})
+}
+
+func WriteBroadcastErrorToResponse(w http.ResponseWriter, code int, err error) {
+	txErr, ok := err.(*types.TxError)
+	if !ok || txErr.Codespace != sdkerrors.RootCodespace {
+		WriteErrorToResponse(w, http.StatusInternalServerError, code, err.Error())
+		return
+	}
+
+	switch txErr.Code {
+	case sdkerrors.ErrInsufficientFunds.ABCICode():
+		WriteErrorToResponse(w, http.StatusBadRequest, code, "insufficient funds: "+txErr.Log)
+	case sdkerrors.ErrInsufficientFee.ABCICode():
+		WriteErrorToResponse(w, http.StatusBadRequest, code, "insufficient fee: "+txErr.Log)
+	case sdkerrors.ErrOutOfGas.ABCICode():
+		WriteErrorToResponse(w, http.StatusBadRequest, code, "out of gas: "+txErr.Log)
+	case sdkerrors.ErrWrongSequence.ABCICode():
+		WriteErrorToResponse(w, http.StatusConflict, code, "account sequence mismatch: "+txErr.Log)
+	default:
+		WriteErrorToResponse(w, http.StatusInternalServerError, code, txErr.Log)
+	}
+}
+
 func WriteResultToResponse(w http.ResponseWriter, status int, result interface{}) {
 	_ = write(w, status, types.Response{
 		Success: true,
@@ -33,33 +95,78 @@ func WriteResultToResponse(w http.ResponseWriter, status int, result interface{}
 
 func ParsePaginationQuery(values url.Values) (pagination *query.PageRequest, err error) {
 	pagination = &query.PageRequest{
-		Limit: 25,
+		Limit: defaultPaginationLimit,
 	}
 
 	if values.Get("key") != "" {
 		pagination.Key, err = hex.DecodeString(values.Get("key"))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid query key; %s", err)
 		}
 	}
 
 	if values.Get("offset") != "" {
+		if strings.HasPrefix(values.Get("offset"), "-") {
+			return nil, fmt.Errorf("invalid query offset; expected non-negative value")
+		}
+
 		pagination.Offset, err = strconv.ParseUint(values.Get("offset"), 0, 64)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid query offset; %s", err)
 		}
 	}
 
 	if values.Get("limit") != "" {
+		if strings.HasPrefix(values.Get("limit"), "-") {
+			return nil, fmt.Errorf("invalid query limit; expected non-negative value")
+		}
+
 		pagination.Limit, err = strconv.ParseUint(values.Get("limit"), 0, 64)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid query limit; %s", err)
 		}
 	}
 
+	if pagination.Limit == 0 {
+		pagination.Limit = defaultPaginationLimit
+	}
+	if pagination.Limit > maxPaginationLimit {
+		pagination.Limit = maxPaginationLimit
+	}
+
 	if values.Get("count_total") != "" {
 		pagination.CountTotal = true
 	}
 
 	return pagination, nil
 }
+
+func ParseSortQuery(values url.Values, def string, keys ...string) (string, error) {
+	sort := values.Get("sort")
+	if sort == "" {
+		return def, nil
+	}
+
+	for _, key := range keys {
+		if sort == key || sort == "-"+key {
+			return sort, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid query sort %s", sort)
+}
+
+func ParseStatusQuery(values url.Values) (hubtypes.Status, error) {
+	s := values.Get("status")
+	if s == "" {
+		return hubtypes.StatusUnknown, nil
+	}
+
+	for _, name := range []string{"Active", "InactivePending", "Inactive"} {
+		if strings.EqualFold(s, name) {
+			return hubtypes.StatusFromString(name), nil
+		}
+	}
+
+	return hubtypes.StatusUnknown, fmt.Errorf("invalid query status %s", s)
+}
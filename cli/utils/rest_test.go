@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+func TestWriteToResponseContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(w http.ResponseWriter)
+		status  int
+		success bool
+	}{
+		{"result", func(w http.ResponseWriter) { WriteResultToResponse(w, http.StatusOK, "ok") }, http.StatusOK, true},
+		{"error", func(w http.ResponseWriter) { WriteErrorToResponse(w, http.StatusBadRequest, 1001, "bad") }, http.StatusBadRequest, false},
+		{"coded error", func(w http.ResponseWriter) {
+			WriteCodedErrorToResponse(w, http.StatusConflict, types.ErrorCode{Code: 1002, ID: "conflict"}, "conflict")
+		}, http.StatusConflict, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set("Content-Type", "text/plain")
+
+			tt.write(w)
+			if v := w.Header().Values("Content-Type"); len(v) != 1 || v[0] != types.ContentTypeJSON {
+				t.Fatalf("expected Content-Type %q, got %q", types.ContentTypeJSON, v)
+			}
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+
+			var res types.Response
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Success != tt.success {
+				t.Fatalf("expected success %v, got %v", tt.success, res.Success)
+			}
+		})
+	}
+}
+
+func TestWriteBroadcastErrorToResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"plain error", errors.New("failed"), http.StatusInternalServerError},
+		{"insufficient funds", types.NewTxError(sdkerrors.RootCodespace, sdkerrors.ErrInsufficientFunds.ABCICode(), "log"), http.StatusBadRequest},
+		{"insufficient fee", types.NewTxError(sdkerrors.RootCodespace, sdkerrors.ErrInsufficientFee.ABCICode(), "log"), http.StatusBadRequest},
+		{"out of gas", types.NewTxError(sdkerrors.RootCodespace, sdkerrors.ErrOutOfGas.ABCICode(), "log"), http.StatusBadRequest},
+		{"wrong sequence", types.NewTxError(sdkerrors.RootCodespace, sdkerrors.ErrWrongSequence.ABCICode(), "log"), http.StatusConflict},
+		{"other codespace", types.NewTxError("vpn", sdkerrors.ErrInsufficientFunds.ABCICode(), "log"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			WriteBroadcastErrorToResponse(w, 1001, tt.err)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestParsePaginationQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		offset uint64
+		limit  uint64
+		err    bool
+	}{
+		{"missing", "", 0, defaultPaginationLimit, false},
+		{"zero limit", "limit=0", 0, defaultPaginationLimit, false},
+		{"limit", "limit=50", 0, 50, false},
+		{"maximum limit", "limit=100", 0, maxPaginationLimit, false},
+		{"oversized limit", "limit=1000000", 0, maxPaginationLimit, false},
+		{"offset", "offset=10&limit=5", 10, 5, false},
+		{"negative limit", "limit=-1", 0, 0, true},
+		{"negative offset", "offset=-1", 0, 0, true},
+		{"non-numeric limit", "limit=ten", 0, 0, true},
+		{"non-numeric offset", "offset=ten", 0, 0, true},
+		{"overflowing limit", "limit=99999999999999999999", 0, 0, true},
+		{"invalid key", "key=xyz", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pagination, err := ParsePaginationQuery(values)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if pagination.Offset != tt.offset || pagination.Limit != tt.limit {
+				t.Fatalf("expected offset %d and limit %d, got %d and %d", tt.offset, tt.limit, pagination.Offset, pagination.Limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"github.com/sentinel-official/desktop-client/cli/types"
+)
+
+// Stopper is implemented by services that roll back everything they changed
+// in one call, rather than leaving that to PreDown, Down and PostDown.
+type Stopper interface {
+	Stop() error
+}
+
+// StopService stops the service with Stop when it has one, and with PreDown,
+// Down and PostDown in turn otherwise. Each of those runs even when the ones
+// before fail, so as much as possible is torn down, and their errors are
+// returned as Errors.
+func StopService(service types.Service) error {
+	if v, ok := service.(Stopper); ok {
+		return v.Stop()
+	}
+
+	var errs []error
+	for _, fn := range []func() error{service.PreDown, service.Down, service.PostDown} {
+		errs = append(errs, fn())
+	}
+
+	return JoinErrors(errs...)
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+// downService records the teardown steps it runs, failing the ones in fail.
+type downService struct {
+	fail  map[string]bool
+	steps []string
+}
+
+func (s *downService) step(name string) error {
+	s.steps = append(s.steps, name)
+	if s.fail[name] {
+		return errors.New(name + " failed")
+	}
+
+	return nil
+}
+
+func (s *downService) Info() []byte                    { return nil }
+func (s *downService) PreUp() error                    { return nil }
+func (s *downService) Up() error                       { return nil }
+func (s *downService) PostUp() error                   { return nil }
+func (s *downService) PreDown() error                  { return s.step("pre-down") }
+func (s *downService) Down() error                     { return s.step("down") }
+func (s *downService) PostDown() error                 { return s.step("post-down") }
+func (s *downService) Transfer() (int64, int64, error) { return 0, 0, nil }
+
+type stopperService struct {
+	downService
+	stopped bool
+}
+
+func (s *stopperService) Stop() error { s.stopped = true; return nil }
+
+func TestStopService(t *testing.T) {
+	tests := []struct {
+		name string
+		fail map[string]bool
+		errs int
+	}{
+		{"clean", nil, 0},
+		{"pre-down fails", map[string]bool{"pre-down": true}, 1},
+		{"every step fails", map[string]bool{"pre-down": true, "down": true, "post-down": true}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &downService{fail: tt.fail}
+
+			err := StopService(service)
+			if len(service.steps) != 3 {
+				t.Fatalf("expected every step to run, ran %v", service.steps)
+			}
+			if tt.errs == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if errs, ok := err.(Errors); !ok || len(errs) != tt.errs {
+				t.Fatalf("expected %d errors, got %v", tt.errs, err)
+			}
+		})
+	}
+
+	t.Run("stopper", func(t *testing.T) {
+		service := &stopperService{}
+		if err := StopService(service); err != nil {
+			t.Fatal(err)
+		}
+		if !service.stopped || len(service.steps) != 0 {
+			t.Fatalf("expected only Stop to run, ran %v", service.steps)
+		}
+	})
+}
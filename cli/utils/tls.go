@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// GenerateSelfSignedCert writes a self-signed ECDSA certificate valid for the
+// loopback addresses and hosts to crtPath, and its key to keyPath.
+func GenerateSelfSignedCert(crtPath, keyPath string, hosts ...string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Sentinel Desktop Client"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			if !ip.IsLoopback() {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			}
+		} else if host != "localhost" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(crtPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// EnsureCert generates a self-signed certificate when neither file exists. It
+// fails when only one of the two does, rather than replacing a user's pair.
+func EnsureCert(crtPath, keyPath string, hosts ...string) error {
+	_, crtErr := os.Stat(crtPath)
+	_, keyErr := os.Stat(keyPath)
+
+	switch {
+	case crtErr == nil && keyErr == nil:
+		return nil
+	case os.IsNotExist(crtErr) && os.IsNotExist(keyErr):
+		return GenerateSelfSignedCert(crtPath, keyPath, hosts...)
+	case crtErr != nil && !os.IsNotExist(crtErr):
+		return crtErr
+	case keyErr != nil && !os.IsNotExist(keyErr):
+		return keyErr
+	default:
+		return fmt.Errorf("found only one of the TLS certificate %s and key %s", crtPath, keyPath)
+	}
+}
+
+// CertFingerprint returns the hex encoded SHA-256 of the first certificate in
+// the PEM file at path, for the GUI to pin.
+func CertFingerprint(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("invalid certificate file %s", path)
+	}
+
+	sum := sha256.Sum256(block.Bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -6,31 +6,25 @@ import (
 )
 
 type Key struct {
-	Name     string `json:"name"`
-	PubKey   string `json:"pub_key"`
-	Address  string `json:"address"`
-	Mnemonic string `json:"mnemonic,omitempty"`
+	Name    string `json:"name"`
+	PubKey  string `json:"pub_key"`
+	Address string `json:"address"`
 }
 
-func NewKeyFromRaw(info keyring.Info, mnemonic string) Key {
+func NewKeyFromRaw(info keyring.Info) Key {
 	return Key{
-		Name:     info.GetName(),
-		PubKey:   bytes.HexBytes(info.GetPubKey().Bytes()).String(),
-		Address:  info.GetAddress().String(),
-		Mnemonic: mnemonic,
+		Name:    info.GetName(),
+		PubKey:  bytes.HexBytes(info.GetPubKey().Bytes()).String(),
+		Address: info.GetAddress().String(),
 	}
 }
 
 type Keys []Key
 
-func NewKeysFromRaw(infos []keyring.Info, mnemonics []string) Keys {
-	if mnemonics == nil {
-		mnemonics = make([]string, len(infos))
-	}
-
+func NewKeysFromRaw(infos []keyring.Info) Keys {
 	items := make(Keys, 0, len(infos))
 	for i := 0; i < len(infos); i++ {
-		items = append(items, NewKeyFromRaw(infos[i], mnemonics[i]))
+		items = append(items, NewKeyFromRaw(infos[i]))
 	}
 
 	return items
@@ -0,0 +1,21 @@
+package node
+
+import (
+	"github.com/sentinel-official/desktop-client/cli/x/common"
+)
+
+type Location struct {
+	City      string  `json:"city"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type Info struct {
+	Moniker   string           `json:"moniker"`
+	Bandwidth common.Bandwidth `json:"bandwidth"`
+	Location  Location         `json:"location"`
+	Peers     int64            `json:"peers"`
+	Type      uint64           `json:"type"`
+	Version   string           `json:"version"`
+}
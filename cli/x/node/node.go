@@ -38,3 +38,15 @@ func NewNodesFromRaw(items nodetypes.Nodes) Nodes {
 
 	return nodes
 }
+
+// Page returns the nodes at the offset, at most limit of them.
+func (n Nodes) Page(offset, limit uint64) Nodes {
+	if offset >= uint64(len(n)) {
+		return Nodes{}
+	}
+	if limit > uint64(len(n))-offset {
+		limit = uint64(len(n)) - offset
+	}
+
+	return n[offset : offset+limit]
+}
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	sessiontypes "github.com/sentinel-official/hub/x/session/types"
@@ -42,3 +44,41 @@ func NewSessionsFromRaw(items sessiontypes.Sessions) Sessions {
 
 	return sessions
 }
+
+func (s Sessions) Sort(key string) {
+	var (
+		desc = strings.HasPrefix(key, "-")
+		less func(i, j int) bool
+	)
+
+	switch strings.TrimPrefix(key, "-") {
+	case "bandwidth":
+		less = func(i, j int) bool {
+			return s[i].Bandwidth.Upload+s[i].Bandwidth.Download < s[j].Bandwidth.Upload+s[j].Bandwidth.Download
+		}
+	default:
+		less = func(i, j int) bool {
+			return s[i].Id < s[j].Id
+		}
+	}
+
+	sort.SliceStable(s, func(i, j int) bool {
+		if desc {
+			return less(j, i)
+		}
+
+		return less(i, j)
+	})
+}
+
+// Page returns the sessions at the offset, at most limit of them.
+func (s Sessions) Page(offset, limit uint64) Sessions {
+	if offset >= uint64(len(s)) {
+		return Sessions{}
+	}
+	if limit > uint64(len(s))-offset {
+		limit = uint64(len(s)) - offset
+	}
+
+	return s[offset : offset+limit]
+}
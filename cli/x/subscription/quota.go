@@ -8,6 +8,7 @@ type Quota struct {
 	Address   string `json:"address"`
 	Consumed  int64  `json:"consumed"`
 	Allocated int64  `json:"allocated"`
+	Remaining int64  `json:"remaining"`
 }
 
 func NewQuotaFromRaw(item *subscriptiontypes.Quota) Quota {
@@ -15,6 +16,7 @@ func NewQuotaFromRaw(item *subscriptiontypes.Quota) Quota {
 		Address:   item.Address,
 		Consumed:  item.Consumed.Int64(),
 		Allocated: item.Allocated.Int64(),
+		Remaining: item.Allocated.Sub(item.Consumed).Int64(),
 	}
 }
 
@@ -266,7 +266,7 @@ export const deleteKeys = (cb = emptyFunc) => (dispatch, getState) => {
             } = getState();
 
             const url = keysDeleteURL(name);
-            Axios.post(url, {}).then((res) => {
+            Axios.delete(url).then((res) => {
                 try {
                     next(null, res?.data?.result);
                 } catch (e) {
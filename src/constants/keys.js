@@ -43,5 +43,5 @@ export const KEYS_DELETE_SUCCESS = 'KEYS_DELETE_SUCCESS';
 
 export const keysDeleteURL = (name) => {
     const baseURL = managerBaseURL();
-    return `${baseURL}/keys/${name}/delete`;
+    return `${baseURL}/keys/${name}`;
 };